@@ -0,0 +1,157 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+const commentsPerPage = 100
+
+var (
+	errUnexpectedStatus  = errors.New("unexpected HTTP status")
+	errUnexpectedContent = errors.New("unexpected content")
+)
+
+// Comment is an issue or pull request comment.
+type Comment struct {
+	Body string `json:"body"`
+	ID   int64  `json:"id"`
+}
+
+// TokenTransport is a http.RoundTripper which authenticates every request with
+// a fixed token, e.g. the workflow's GITHUB_TOKEN.
+type TokenTransport struct {
+	Base  http.RoundTripper
+	Token string
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *TokenTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+t.Token)
+	return base.RoundTrip(req) //nolint:wrapcheck // just a wrapper
+}
+
+// UpsertComment creates a comment on the given pull request, or updates the
+// existing comment containing marker so the pull request only ever has one.
+func (c *Client) UpsertComment(baseRepoURL, repoName string, number int, marker, body string) (Comment, error) {
+	baseURL, err := url.Parse(baseRepoURL)
+	if err != nil {
+		return Comment{}, fmt.Errorf("parsing base repo URL: %w", err)
+	}
+
+	existing, err := c.findComment(baseURL, repoName, number, marker)
+	if err != nil {
+		return Comment{}, err
+	}
+
+	payload, err := json.Marshal(struct {
+		Body string `json:"body"`
+	}{Body: body})
+	if err != nil {
+		return Comment{}, fmt.Errorf("marshalling comment: %w", err)
+	}
+
+	var ret Comment
+	if existing != nil {
+		commentURL := baseURL.JoinPath("repos", repoName, "issues", "comments", strconv.FormatInt(existing.ID, 10))
+		log.Printf("updating comment %d on pull request #%d", existing.ID, number)
+		err = c.doJSON(http.MethodPatch, commentURL, payload, http.StatusOK, &ret)
+	} else {
+		commentsURL := baseURL.JoinPath("repos", repoName, "issues", strconv.Itoa(number), "comments")
+		log.Printf("creating comment on pull request #%d", number)
+		err = c.doJSON(http.MethodPost, commentsURL, payload, http.StatusCreated, &ret)
+	}
+	if err != nil {
+		return Comment{}, err
+	}
+	return ret, nil
+}
+
+// findComment returns the first comment on the pull request containing marker, if any.
+func (c *Client) findComment(baseURL *url.URL, repoName string, number int, marker string) (*Comment, error) {
+	commentsURL := baseURL.JoinPath("repos", repoName, "issues", strconv.Itoa(number), "comments")
+	for page := 1; ; page++ {
+		q := commentsURL.Query()
+		q.Set("per_page", strconv.Itoa(commentsPerPage))
+		q.Set("page", strconv.Itoa(page))
+		commentsURL.RawQuery = q.Encode()
+
+		var comments []Comment
+		if err := c.doJSON(http.MethodGet, commentsURL, nil, http.StatusOK, &comments); err != nil {
+			return nil, err
+		}
+		for i := range comments {
+			if strings.Contains(comments[i].Body, marker) {
+				return &comments[i], nil
+			}
+		}
+		if len(comments) < commentsPerPage {
+			return nil, nil
+		}
+	}
+}
+
+func (c *Client) doJSON(method string, u *url.URL, payload []byte, wantStatus int, out any) error {
+	var body io.Reader
+	if payload != nil {
+		body = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequestWithContext(c.ctx, method, u.String(), body)
+	if err != nil {
+		return fmt.Errorf("error creating request: %w", err)
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	client := &http.Client{Transport: c.rt}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error executing request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	respBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("error reading response body: %w", err)
+	}
+	if resp.StatusCode != wantStatus {
+		statusErr := errUnexpectedStatus
+		if resp.StatusCode == http.StatusNotFound {
+			statusErr = ErrNotFound
+		}
+		return fmt.Errorf("%w: %s %s: %d: %s", statusErr, method, u.Path, resp.StatusCode, respBytes)
+	}
+	if err := json.Unmarshal(respBytes, out); err != nil {
+		return fmt.Errorf("error decoding response body: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+const testMarker = "<!-- test-marker -->"
+
+// fakeIssues is a minimal stand-in for the GitHub issue comments API.
+type fakeIssues struct {
+	comments map[int64]string
+	auth     []string
+	nextID   int64
+	mu       sync.Mutex
+}
+
+func (f *fakeIssues) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.auth = append(f.auth, r.Header.Get("Authorization"))
+
+	var req Comment
+	if r.Body != nil {
+		//nolint:errcheck // GET requests have no body
+		json.NewDecoder(r.Body).Decode(&req)
+	}
+	switch {
+	case r.Method == http.MethodGet && r.URL.Path == "/repos/foo/bar/issues/7/comments":
+		comments := []Comment{}
+		// Only the first page has comments.
+		if r.URL.Query().Get("page") == "1" {
+			for id, body := range f.comments {
+				comments = append(comments, Comment{ID: id, Body: body})
+			}
+		}
+		json.NewEncoder(w).Encode(comments) //nolint:errcheck
+	case r.Method == http.MethodPost && r.URL.Path == "/repos/foo/bar/issues/7/comments":
+		f.nextID++
+		f.comments[f.nextID] = req.Body
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(Comment{ID: f.nextID, Body: req.Body}) //nolint:errcheck
+	case r.Method == http.MethodPatch && strings.HasPrefix(r.URL.Path, "/repos/foo/bar/issues/comments/"):
+		var id int64
+		fmt.Sscanf(strings.TrimPrefix(r.URL.Path, "/repos/foo/bar/issues/comments/"), "%d", &id) //nolint:errcheck
+		if _, ok := f.comments[id]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		f.comments[id] = req.Body
+		json.NewEncoder(w).Encode(Comment{ID: id, Body: req.Body}) //nolint:errcheck
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestUpsertComment(t *testing.T) {
+	t.Parallel()
+	fake := &fakeIssues{
+		comments: map[int64]string{},
+		nextID:   100,
+	}
+	fake.comments[1] = "an unrelated comment"
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+
+	c := NewClient(context.Background())
+	c.SetTransport(&TokenTransport{Token: "ghs_foo"})
+
+	created, err := c.UpsertComment(server.URL, "foo/bar", 7, testMarker, testMarker+"\nfirst")
+	if err != nil {
+		t.Fatalf("UpsertComment() create: %v", err)
+	}
+	if created.ID != 101 {
+		t.Errorf("expected new comment 101, got %d", created.ID)
+	}
+
+	updated, err := c.UpsertComment(server.URL, "foo/bar", 7, testMarker, testMarker+"\nsecond")
+	if err != nil {
+		t.Fatalf("UpsertComment() update: %v", err)
+	}
+	if updated.ID != created.ID {
+		t.Errorf("expected comment %d to be updated, got %d", created.ID, updated.ID)
+	}
+	if len(fake.comments) != 2 {
+		t.Errorf("expected 2 comments, got %d: %v", len(fake.comments), fake.comments)
+	}
+	if got := fake.comments[created.ID]; got != testMarker+"\nsecond" {
+		t.Errorf("unexpected comment body: %q", got)
+	}
+	for _, auth := range fake.auth {
+		if auth != "Bearer ghs_foo" {
+			t.Errorf("unexpected Authorization header: %q", auth)
+		}
+	}
+}
+
+func TestUpsertComment_error(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(server.Close)
+
+	c := NewClient(context.Background())
+	c.SetTransport(http.DefaultTransport)
+	if _, err := c.UpsertComment(server.URL, "foo/bar", 7, testMarker, "body"); err == nil {
+		t.Error("expected error for forbidden response")
+	}
+}
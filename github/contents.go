@@ -0,0 +1,165 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// ErrNotFound is returned when a repository, branch or file does not exist.
+var ErrNotFound = errors.New("not found")
+
+// File is a file of a repository branch.
+type File struct {
+	Content []byte
+	// SHA is the blob SHA of the file, needed to update it.
+	SHA string
+}
+
+// BranchExists reports whether the repository has the branch.
+func (c *Client) BranchExists(baseRepoURL, repoName, branch string) (bool, error) {
+	baseURL, err := url.Parse(baseRepoURL)
+	if err != nil {
+		return false, fmt.Errorf("parsing base repo URL: %w", err)
+	}
+	var ret json.RawMessage
+	err = c.doJSON(http.MethodGet, baseURL.JoinPath("repos", repoName, "branches", branch), nil, http.StatusOK, &ret)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return false, nil
+	case err != nil:
+		return false, err
+	}
+	return true, nil
+}
+
+// GetFile returns a file of a branch of the repository, or ErrNotFound.
+func (c *Client) GetFile(baseRepoURL, repoName, branch, path string) (File, error) {
+	baseURL, err := url.Parse(baseRepoURL)
+	if err != nil {
+		return File{}, fmt.Errorf("parsing base repo URL: %w", err)
+	}
+	contentsURL := baseURL.JoinPath("repos", repoName, "contents", path)
+	q := contentsURL.Query()
+	q.Set("ref", branch)
+	contentsURL.RawQuery = q.Encode()
+
+	var ret struct {
+		Content  string `json:"content"`
+		Encoding string `json:"encoding"`
+		SHA      string `json:"sha"`
+	}
+	if err := c.doJSON(http.MethodGet, contentsURL, nil, http.StatusOK, &ret); err != nil {
+		return File{}, err
+	}
+	if ret.Encoding != "base64" {
+		return File{}, fmt.Errorf("%w: %s encoding of %s", errUnexpectedContent, ret.Encoding, path)
+	}
+	// The content is wrapped at 60 characters.
+	content, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(ret.Content, "\n", ""))
+	if err != nil {
+		return File{}, fmt.Errorf("decoding %s: %w", path, err)
+	}
+	return File{Content: content, SHA: ret.SHA}, nil
+}
+
+// PutFile creates or updates a file on an existing branch of the repository.
+// Updates need the SHA of the file they replace.
+func (c *Client) PutFile(baseRepoURL, repoName, branch, path, message string, content []byte, sha string) error {
+	baseURL, err := url.Parse(baseRepoURL)
+	if err != nil {
+		return fmt.Errorf("parsing base repo URL: %w", err)
+	}
+	payload, err := json.Marshal(struct {
+		Message string `json:"message"`
+		Content string `json:"content"`
+		Branch  string `json:"branch"`
+		SHA     string `json:"sha,omitempty"`
+	}{
+		Message: message,
+		Content: base64.StdEncoding.EncodeToString(content),
+		Branch:  branch,
+		SHA:     sha,
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling file: %w", err)
+	}
+
+	wantStatus := http.StatusOK
+	if sha == "" {
+		wantStatus = http.StatusCreated
+	}
+	log.Printf("writing %s to branch %s", path, branch)
+	var ret json.RawMessage
+	return c.doJSON(http.MethodPut, baseURL.JoinPath("repos", repoName, "contents", path), payload, wantStatus, &ret)
+}
+
+// CreateOrphanBranch creates a branch with no history holding a single file.
+func (c *Client) CreateOrphanBranch(baseRepoURL, repoName, branch, path, message string, content []byte) error {
+	baseURL, err := url.Parse(baseRepoURL)
+	if err != nil {
+		return fmt.Errorf("parsing base repo URL: %w", err)
+	}
+	gitURL := baseURL.JoinPath("repos", repoName, "git")
+	var object struct {
+		SHA string `json:"sha"`
+	}
+
+	type treeEntry struct {
+		Path    string `json:"path"`
+		Mode    string `json:"mode"`
+		Type    string `json:"type"`
+		Content string `json:"content"`
+	}
+	payload, err := json.Marshal(struct {
+		Tree []treeEntry `json:"tree"`
+	}{Tree: []treeEntry{{Path: path, Mode: "100644", Type: "blob", Content: string(content)}}})
+	if err != nil {
+		return fmt.Errorf("marshalling tree: %w", err)
+	}
+	if err := c.doJSON(http.MethodPost, gitURL.JoinPath("trees"), payload, http.StatusCreated, &object); err != nil {
+		return err
+	}
+
+	payload, err = json.Marshal(struct {
+		Message string   `json:"message"`
+		Tree    string   `json:"tree"`
+		Parents []string `json:"parents"`
+	}{Message: message, Tree: object.SHA, Parents: []string{}})
+	if err != nil {
+		return fmt.Errorf("marshalling commit: %w", err)
+	}
+	if err := c.doJSON(http.MethodPost, gitURL.JoinPath("commits"), payload, http.StatusCreated, &object); err != nil {
+		return err
+	}
+
+	payload, err = json.Marshal(struct {
+		Ref string `json:"ref"`
+		SHA string `json:"sha"`
+	}{Ref: "refs/heads/" + branch, SHA: object.SHA})
+	if err != nil {
+		return fmt.Errorf("marshalling ref: %w", err)
+	}
+	log.Printf("creating branch %s", branch)
+	var ret json.RawMessage
+	return c.doJSON(http.MethodPost, gitURL.JoinPath("refs"), payload, http.StatusCreated, &ret)
+}
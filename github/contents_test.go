@@ -0,0 +1,177 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package github
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeContents is a minimal stand-in for the GitHub contents and git data APIs
+// of the foo/bar repository. Branches map file paths to contents.
+type fakeContents struct {
+	branches map[string]map[string]string
+	trees    map[string]map[string]string
+	commits  map[string]string
+	nextSHA  int
+	mu       sync.Mutex
+}
+
+func (f *fakeContents) sha() string {
+	f.nextSHA++
+	return fmt.Sprintf("sha%d", f.nextSHA)
+}
+
+//nolint:errcheck // test server
+func (f *fakeContents) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var req struct {
+		Message string `json:"message"`
+		Content string `json:"content"`
+		Branch  string `json:"branch"`
+		SHA     string `json:"sha"`
+		Tree    any    `json:"tree"`
+		Ref     string `json:"ref"`
+	}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&req)
+	}
+	const repo = "/repos/foo/bar/"
+	path := strings.TrimPrefix(r.URL.Path, repo)
+	switch {
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "branches/"):
+		if _, ok := f.branches[strings.TrimPrefix(path, "branches/")]; !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Write([]byte(`{}`))
+	case r.Method == http.MethodGet && strings.HasPrefix(path, "contents/"):
+		content, ok := f.branches[r.URL.Query().Get("ref")][strings.TrimPrefix(path, "contents/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{
+			"content":  base64.StdEncoding.EncodeToString([]byte(content)),
+			"encoding": "base64",
+			"sha":      "blob-" + content,
+		})
+	case r.Method == http.MethodPut && strings.HasPrefix(path, "contents/"):
+		files, ok := f.branches[req.Branch]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		file := strings.TrimPrefix(path, "contents/")
+		old, exists := files[file]
+		if exists != (req.SHA != "") || (exists && req.SHA != "blob-"+old) {
+			w.WriteHeader(http.StatusConflict)
+			return
+		}
+		content, _ := base64.StdEncoding.DecodeString(req.Content)
+		files[file] = string(content)
+		if !exists {
+			w.WriteHeader(http.StatusCreated)
+		}
+		w.Write([]byte(`{}`))
+	case r.Method == http.MethodPost && path == "git/trees":
+		entries, _ := req.Tree.([]any)
+		tree := map[string]string{}
+		for _, e := range entries {
+			entry, _ := e.(map[string]any)
+			tree[entry["path"].(string)] = entry["content"].(string)
+		}
+		sha := f.sha()
+		f.trees[sha] = tree
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"sha": sha})
+	case r.Method == http.MethodPost && path == "git/commits":
+		tree, _ := req.Tree.(string)
+		sha := f.sha()
+		f.commits[sha] = tree
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"sha": sha})
+	case r.Method == http.MethodPost && path == "git/refs":
+		f.branches[strings.TrimPrefix(req.Ref, "refs/heads/")] = f.trees[f.commits[req.SHA]]
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{}`))
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestContents(t *testing.T) {
+	t.Parallel()
+	fake := &fakeContents{
+		branches: map[string]map[string]string{"main": {"README.md": "# bar\n"}},
+		trees:    map[string]map[string]string{},
+		commits:  map[string]string{},
+	}
+	server := httptest.NewServer(fake)
+	t.Cleanup(server.Close)
+	c := NewClient(context.Background())
+	c.SetTransport(http.DefaultTransport)
+
+	for branch, want := range map[string]bool{"main": true, "history": false} {
+		got, err := c.BranchExists(server.URL, "foo/bar", branch)
+		if err != nil {
+			t.Fatalf("BranchExists(%s): %v", branch, err)
+		}
+		if got != want {
+			t.Errorf("BranchExists(%s) = %t, want %t", branch, got, want)
+		}
+	}
+
+	if _, err := c.GetFile(server.URL, "foo/bar", "main", "history.jsonl"); !errors.Is(err, ErrNotFound) {
+		t.Errorf("GetFile() of a missing file: %v, want %v", err, ErrNotFound)
+	}
+	file, err := c.GetFile(server.URL, "foo/bar", "main", "README.md")
+	if err != nil {
+		t.Fatalf("GetFile(): %v", err)
+	}
+	if string(file.Content) != "# bar\n" {
+		t.Errorf("GetFile() content: %q", file.Content)
+	}
+
+	if err := c.PutFile(server.URL, "foo/bar", "main", "README.md", "update", []byte("# foo\n"), file.SHA); err != nil {
+		t.Fatalf("PutFile() update: %v", err)
+	}
+	if err := c.PutFile(server.URL, "foo/bar", "main", "NOTES.md", "create", []byte("notes\n"), ""); err != nil {
+		t.Fatalf("PutFile() create: %v", err)
+	}
+	if err := c.PutFile(server.URL, "foo/bar", "main", "README.md", "stale", []byte("# baz\n"), file.SHA); err == nil {
+		t.Error("PutFile() with a stale SHA: got no error")
+	}
+	if got := fake.branches["main"]; got["README.md"] != "# foo\n" || got["NOTES.md"] != "notes\n" {
+		t.Errorf("unexpected files on main: %v", got)
+	}
+
+	if err := c.CreateOrphanBranch(server.URL, "foo/bar", "history", "history.jsonl", "init", []byte("{}\n")); err != nil {
+		t.Fatalf("CreateOrphanBranch(): %v", err)
+	}
+	if got := fake.branches["history"]; len(got) != 1 || got["history.jsonl"] != "{}\n" {
+		t.Errorf("unexpected files on history: %v", got)
+	}
+}
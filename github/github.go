@@ -34,6 +34,8 @@ import (
 type RepoInfo struct {
 	Repo      repo `json:"repository"`
 	respBytes []byte
+	// Number is the pull request number, only set for pull request events.
+	Number int `json:"number"`
 }
 
 type repo struct {
@@ -18,7 +18,14 @@
 package cli
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
@@ -27,13 +34,15 @@ import (
 )
 
 const (
-	cmdUsage     = `--owner example_org [--repos <repo1,repo2,repo3>]`
+	cmdUsage     = `--owner example_org [--repos <repo1,repo2,repo3>] [--dry-run] [--output table|json]`
 	cmdDescShort = "Scorecard GitHub Action installer"
 	cmdDescLong  = `
 The Scorecard GitHub Action installer simplifies the installation of the
 scorecard GitHub Action by creating pull requests through the command line.`
 )
 
+var errReposFailed = errors.New("installation failed for some repositories")
+
 // New creates a new instance of the scorecard action installation command.
 func New(o *options.Options) *cobra.Command {
 	cmd := &cobra.Command{
@@ -49,6 +58,8 @@ func New(o *options.Options) *cobra.Command {
 			return nil
 		},
 		RunE: func(cmd *cobra.Command, args []string) error {
+			// Failed repositories are in the report, usage wouldn't help.
+			cmd.SilenceUsage = true
 			return rootCmd(o)
 		},
 	}
@@ -59,10 +70,50 @@ func New(o *options.Options) *cobra.Command {
 
 // rootCmd runs scorecard checks given a set of arguments.
 func rootCmd(o *options.Options) error {
-	err := install.Run(o)
+	report, err := install.Run(o)
 	if err != nil {
 		return fmt.Errorf("running scorecard installation: %w", err)
 	}
+	if err := printReport(os.Stdout, report, o.OutputFormat()); err != nil {
+		return err
+	}
+	if report.Failed() {
+		return fmt.Errorf("%w: %d of %d", errReposFailed, report.Summary[install.StatusFailed], len(report.Repositories))
+	}
+
+	return nil
+}
+
+// printReport writes the report as JSON, or as a table followed by the number
+// of repositories per status.
+func printReport(w io.Writer, report *install.Report, format string) error {
+	if format == options.OutputJSON {
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		if err := encoder.Encode(report); err != nil {
+			return fmt.Errorf("writing report: %w", err)
+		}
+		return nil
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "REPOSITORY\tSTATUS\tPULL REQUEST\tERROR")
+	for _, r := range report.Repositories {
+		errMsg := r.Error
+		if r.ErrorKind != "" {
+			errMsg = fmt.Sprintf("%s: %s", r.ErrorKind, r.Error)
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", r.Name, r.Status, r.PullRequestURL, errMsg)
+	}
+	if err := tw.Flush(); err != nil {
+		return fmt.Errorf("writing report: %w", err)
+	}
 
+	statuses := make([]string, 0, len(report.Summary))
+	for status, n := range report.Summary {
+		statuses = append(statuses, fmt.Sprintf("%d %s", n, status))
+	}
+	sort.Strings(statuses)
+	fmt.Fprintf(w, "\n%d repositories: %s\n", len(report.Repositories), strings.Join(statuses, ", "))
 	return nil
 }
@@ -22,6 +22,7 @@ import (
 	"fmt"
 	"log"
 	"net/http"
+	"strings"
 
 	gogh "github.com/google/go-github/v46/github"
 
@@ -48,24 +49,83 @@ func New(ctx context.Context) *Client {
 // Modeled after
 // https://github.com/kubernetes-sigs/release-sdk/blob/e23d2c82bbb41a007cdf019c30930e8fd2649c01/github/github.go
 
-// GetRepositoriesByOrg // TODO(lint): Needs a comment.
+// reposPerPage is the largest page size the GitHub API allows.
+const reposPerPage = 100
+
+// GetRepositoriesByOwner returns all repositories of the organization or user.
+func (c *Client) GetRepositoriesByOwner(
+	ctx context.Context,
+	owner string,
+) ([]*gogh.Repository, error) {
+	user, _, err := c.Users.Get(ctx, owner)
+	if err != nil {
+		return nil, fmt.Errorf("getting owner: %w", err)
+	}
+	if user.GetType() == "Organization" {
+		return c.GetRepositoriesByOrg(ctx, owner)
+	}
+	return c.GetRepositoriesByUser(ctx, owner)
+}
+
+// GetRepositoriesByOrg returns all repositories of the organization, following
+// the pagination of the GitHub API.
 func (c *Client) GetRepositoriesByOrg(
 	ctx context.Context,
 	owner string,
-) ([]*gogh.Repository, *gogh.Response, error) {
-	repos, resp, err := c.Repositories.ListByOrg(
-		ctx,
-		owner,
-		// TODO(install): Does this need to parameterized?
-		&gogh.RepositoryListByOrgOptions{
-			Type: "all",
-		},
-	)
+) ([]*gogh.Repository, error) {
+	opts := &gogh.RepositoryListByOrgOptions{
+		Type:        "all",
+		ListOptions: gogh.ListOptions{PerPage: reposPerPage},
+	}
+	return listAllRepositories(&opts.ListOptions, func() ([]*gogh.Repository, *gogh.Response, error) {
+		return c.Repositories.ListByOrg(ctx, owner, opts)
+	})
+}
+
+// GetRepositoriesByUser returns all repositories owned by the user, following
+// the pagination of the GitHub API. Private repositories are only listed when
+// the user is the authenticated one.
+func (c *Client) GetRepositoriesByUser(
+	ctx context.Context,
+	user string,
+) ([]*gogh.Repository, error) {
+	authenticated, _, err := c.Users.Get(ctx, "")
 	if err != nil {
-		return repos, resp, fmt.Errorf("getting repositories: %w", err)
+		return nil, fmt.Errorf("getting authenticated user: %w", err)
 	}
 
-	return repos, resp, nil
+	opts := &gogh.RepositoryListOptions{
+		Type:        "owner",
+		ListOptions: gogh.ListOptions{PerPage: reposPerPage},
+	}
+	if strings.EqualFold(authenticated.GetLogin(), user) {
+		// The authenticated user's own listing includes private repositories,
+		// it doesn't allow a type along with the affiliation.
+		user, opts.Type, opts.Affiliation = "", "", "owner"
+	}
+	return listAllRepositories(&opts.ListOptions, func() ([]*gogh.Repository, *gogh.Response, error) {
+		return c.Repositories.List(ctx, user, opts)
+	})
+}
+
+// listAllRepositories calls list for every page of repositories, updating the
+// page of the list options in between.
+func listAllRepositories(
+	opts *gogh.ListOptions,
+	list func() ([]*gogh.Repository, *gogh.Response, error),
+) ([]*gogh.Repository, error) {
+	var all []*gogh.Repository
+	for {
+		repos, resp, err := list()
+		if err != nil {
+			return nil, fmt.Errorf("getting repositories: %w", err)
+		}
+		all = append(all, repos...)
+		if resp.NextPage == 0 {
+			return all, nil
+		}
+		opts.Page = resp.NextPage
+	}
 }
 
 // GetRepository // TODO(lint): Needs a comment.
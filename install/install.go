@@ -21,8 +21,13 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"os"
+	"net/http"
 	"path"
+	"slices"
+	"strings"
+	"text/template"
+
+	gogh "github.com/google/go-github/v46/github"
 
 	"github.com/ossf/scorecard-action/install/github"
 	"github.com/ossf/scorecard-action/install/options"
@@ -51,48 +56,48 @@ To report any issues with this tool, see [here](https://github.com/ossf/scorecar
 	}
 )
 
-// Run adds the OpenSSF Scorecard workflow to all repositories under the given
-// organization.
-// TODO(install): Improve description.
+// Run adds the OpenSSF Scorecard workflow to all repositories of the given
+// organization or user, and reports the outcome for each of them. With DryRun set, it
+// only reports what it would do. Failing repositories don't stop the run, see
+// Report.Failed.
 // TODO(install): Accept a context instead of setting one.
-func Run(o *options.Options) error {
+func Run(o *options.Options) (*Report, error) {
 	err := o.Validate()
 	if err != nil {
-		return fmt.Errorf("validating installation options: %w", err)
+		return nil, fmt.Errorf("validating installation options: %w", err)
 	}
 
 	// Get github user client.
 	ctx := context.Background()
 	gh := github.New(ctx)
 
-	// If not provided, get all repositories under organization.
+	// If not provided, get all repositories of the organization or user.
 	if len(o.Repositories) == 0 {
-		log.Print("No repositories provided. Fetching all repositories under organization.")
-		repos, _, err := gh.GetRepositoriesByOrg(ctx, o.Owner)
+		log.Print("No repositories provided. Fetching all repositories of the owner.")
+		o.Repositories, err = listRepositories(ctx, gh, o)
 		if err != nil {
-			return fmt.Errorf("getting repos for owner (%s): %w", o.Owner, err)
-		}
-
-		// Convert to list of repository names.
-		for _, repo := range repos {
-			o.Repositories = append(o.Repositories, *repo.Name)
+			return nil, fmt.Errorf("getting repos for owner (%s): %w", o.Owner, err)
 		}
 	}
 
-	// Get yml file into byte array.
-	workflowContent, err := os.ReadFile(o.ConfigPath)
+	workflow, err := parseWorkflow(o.ConfigPath)
 	if err != nil {
-		return fmt.Errorf("reading scorecard workflow file: %w", err)
+		return nil, err
+	}
+	if err := checkWorkflowVariables(workflow, o); err != nil {
+		return nil, err
 	}
 
+	report := &Report{Owner: o.Owner, DryRun: o.DryRun}
+
 	// Process each repository.
-	// TODO: Capture repo access errors
 	for _, repoName := range o.Repositories {
 		log.Printf("Processing repository: %s", repoName)
-		err := processRepo(ctx, gh, o.Owner, repoName, workflowContent)
-		if err != nil {
-			log.Printf("processing repository: %+v", err)
+		result := processRepo(ctx, gh, o, repoName, workflow)
+		if result.Error != "" {
+			log.Printf("processing repository: %s", result.Error)
 		}
+		report.add(result)
 
 		log.Printf(
 			"finished processing repository %s",
@@ -100,27 +105,132 @@ func Run(o *options.Options) error {
 		)
 	}
 
-	return nil
+	return report, nil
+}
+
+// listRepositories returns the names of the owner's repositories that match
+// the filters of the options.
+func listRepositories(ctx context.Context, gh *github.Client, o *options.Options) ([]string, error) {
+	repos, err := gh.GetRepositoriesByOwner(ctx, o.Owner)
+	if err != nil {
+		return nil, err //nolint:wrapcheck // already wrapped
+	}
+
+	var names []string
+	for _, repo := range repos {
+		if !matchesFilters(repo, o) {
+			log.Printf("skipping repo (%s) since it doesn't match the filters", repo.GetName())
+			continue
+		}
+		names = append(names, repo.GetName())
+	}
+	return names, nil
+}
+
+// matchesFilters reports whether the repository matches the visibility,
+// archived, fork, topic and language filters of the options.
+func matchesFilters(repo *gogh.Repository, o *options.Options) bool {
+	if repo.GetArchived() && !o.IncludeArchived {
+		return false
+	}
+	if repo.GetFork() && o.ExcludeForks {
+		return false
+	}
+	if o.Visibility != "" && o.Visibility != options.VisibilityAll && repoVisibility(repo) != o.Visibility {
+		return false
+	}
+	if len(o.Topics) > 0 && !slices.ContainsFunc(repo.Topics, func(topic string) bool {
+		return slices.ContainsFunc(o.Topics, func(t string) bool { return strings.EqualFold(t, topic) })
+	}) {
+		return false
+	}
+	if len(o.Languages) > 0 && !slices.ContainsFunc(o.Languages, func(l string) bool {
+		return strings.EqualFold(l, repo.GetLanguage())
+	}) {
+		return false
+	}
+	return true
+}
+
+// repoVisibility returns the visibility of the repository, falling back to
+// its private flag when the API doesn't return one.
+func repoVisibility(repo *gogh.Repository) string {
+	if v := repo.GetVisibility(); v != "" {
+		return v
+	}
+	if repo.GetPrivate() {
+		return options.VisibilityPrivate
+	}
+	return options.VisibilityPublic
 }
 
+// processRepo installs the workflow, rendered for the repository, if needed
+// and possible.
 func processRepo(
+	ctx context.Context,
+	gh *github.Client,
+	o *options.Options,
+	repoName string,
+	workflow *template.Template,
+) RepoResult {
+	plan, err := resolveRepo(ctx, gh, o.Owner, repoName)
+	if err != nil {
+		return failedResult(repoName, err)
+	}
+	if plan.status != StatusWouldCreatePR {
+		return RepoResult{Name: repoName, Status: plan.status}
+	}
+
+	// Rendered in dry runs too, so that template errors show up.
+	workflowContent, err := renderWorkflow(workflow, o, repoName, plan.defaultBranch.GetName())
+	if err != nil {
+		return failedResult(repoName, err)
+	}
+	if o.DryRun {
+		return RepoResult{Name: repoName, Status: plan.status}
+	}
+
+	prURL, err := installRepo(ctx, gh, o.Owner, repoName, plan.defaultBranch, workflowContent)
+	if err != nil {
+		return failedResult(repoName, err)
+	}
+	return RepoResult{Name: repoName, Status: StatusCreatedPR, PullRequestURL: prURL}
+}
+
+// repoPlan is what installing the workflow on a repository would do.
+type repoPlan struct {
+	defaultBranch *gogh.Branch
+	status        string
+}
+
+// resolveRepo finds out whether the workflow can be installed on the
+// repository, without changing it.
+func resolveRepo(
 	ctx context.Context,
 	gh *github.Client,
 	owner, repoName string,
-	workflowContent []byte,
-) error {
+) (repoPlan, error) {
 	// Get repo metadata.
 	log.Printf("getting repo metadata for %s", repoName)
+	// A repository that can't be read is a failure, reported with the kind of
+	// error, since it was either requested or just listed.
 	repo, _, err := gh.GetRepository(ctx, owner, repoName)
 	if err != nil {
-		return fmt.Errorf(
+		return repoPlan{}, fmt.Errorf(
 			"getting repository: %w",
 			err,
 		)
 	}
+	if repo.GetArchived() {
+		log.Printf("skipping repo (%s) since it is archived", repoName)
+		return repoPlan{status: StatusArchived}, nil
+	}
+	if perms := repo.GetPermissions(); perms != nil && !perms["push"] {
+		log.Printf("skipping repo (%s) since pushing to it is not allowed", repoName)
+		return repoPlan{status: StatusNoAccess}, nil
+	}
 
 	// Get head commit SHA of default branch.
-	// TODO: Capture branch access errors
 	defaultBranch, _, err := gh.GetBranch(
 		ctx,
 		owner,
@@ -129,23 +239,20 @@ func processRepo(
 		true,
 	)
 	if err != nil {
-		return fmt.Errorf(
+		return repoPlan{}, fmt.Errorf(
 			"getting default branch for %s: %w",
 			repoName,
 			err,
 		)
 	}
 
-	defaultBranchSHA := defaultBranch.Commit.SHA
-
 	// Skip if scorecard file already exists in workflows folder.
-	workflowExists := false
-	for i, f := range workflowFiles {
+	for _, f := range workflowFiles {
 		log.Printf(
 			"checking for scorecard workflow file (%s)",
 			f,
 		)
-		scoreFileContent, _, _, err := gh.GetContents(
+		scoreFileContent, _, resp, err := gh.GetContents(
 			ctx,
 			owner,
 			repoName,
@@ -159,85 +266,105 @@ func processRepo(
 				f,
 			)
 
-			workflowExists = true
-			break
+			return repoPlan{status: StatusWorkflowPresent}, nil
 		}
-		if err != nil && i == len(workflowFiles)-1 {
-			log.Printf("could not find a scorecard workflow file: %+v", err)
+		// Only a missing file means there is no workflow yet.
+		if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+			return repoPlan{}, fmt.Errorf(
+				"checking for scorecard workflow file %s in %s: %w",
+				f,
+				repoName,
+				err,
+			)
 		}
 	}
 
-	if !workflowExists {
-		// Skip if branch scorecard already exists.
-		scorecardBranch, _, err := gh.GetBranch(
-			ctx,
-			owner,
+	// Skip if branch scorecard already exists.
+	_, resp, err := gh.GetBranch(
+		ctx,
+		owner,
+		repoName,
+		pullRequestBranch,
+		true,
+	)
+	if err != nil && (resp == nil || resp.StatusCode != http.StatusNotFound) {
+		return repoPlan{}, fmt.Errorf(
+			"checking for scorecard action installation branch in %s: %w",
+			repoName,
+			err,
+		)
+	}
+	if err == nil {
+		log.Printf(
+			"skipping repo (%s) since the scorecard action installation branch already exists",
 			repoName,
-			pullRequestBranch,
-			true,
 		)
-		if scorecardBranch != nil || err == nil {
-			log.Printf(
-				"skipping repo (%s) since the scorecard action installation branch already exists",
-				repoName,
-			)
 
-			return nil
-		}
+		return repoPlan{status: StatusBranchExists}, nil
+	}
 
-		// Create new branch using a reference that stores the new commit hash.
-		// TODO: Capture ref creation errors
-		ref := github.CreateGitRefOptions(branchReference, defaultBranchSHA)
-		_, _, err = gh.CreateGitRef(ctx, owner, repoName, ref)
-		if err != nil {
-			return fmt.Errorf(
-				"creating scorecard action installation branch for %s: %w",
-				repoName,
-				err,
-			)
-		}
+	return repoPlan{defaultBranch: defaultBranch, status: StatusWouldCreatePR}, nil
+}
 
-		// Create file in repository.
-		// TODO: Capture file creation errors
-		opts := github.CreateRepositoryContentFileOptions(
-			workflowContent,
-			commitMessage,
-			pullRequestBranch,
+// installRepo creates the installation branch with the workflow, and opens a
+// pull request against the default branch. It returns the URL of the pull
+// request.
+func installRepo(
+	ctx context.Context,
+	gh *github.Client,
+	owner, repoName string,
+	defaultBranch *gogh.Branch,
+	workflowContent []byte,
+) (string, error) {
+	// Create new branch using a reference that stores the new commit hash.
+	ref := github.CreateGitRefOptions(branchReference, defaultBranch.Commit.SHA)
+	_, _, err := gh.CreateGitRef(ctx, owner, repoName, ref)
+	if err != nil {
+		return "", fmt.Errorf(
+			"creating scorecard action installation branch for %s: %w",
+			repoName,
+			err,
 		)
-		_, _, err = gh.CreateFile(
-			ctx,
-			owner,
+	}
+
+	// Create file in repository.
+	opts := github.CreateRepositoryContentFileOptions(
+		workflowContent,
+		commitMessage,
+		pullRequestBranch,
+	)
+	_, _, err = gh.CreateFile(
+		ctx,
+		owner,
+		repoName,
+		workflowFilePath,
+		opts,
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"creating scorecard workflow file for %s: %w",
 			repoName,
-			workflowFilePath,
-			opts,
+			err,
 		)
-		if err != nil {
-			return fmt.Errorf(
-				"creating scorecard workflow file for %s: %w",
-				repoName,
-				err,
-			)
-		}
+	}
 
-		// Create pull request.
-		// TODO: Capture pull request creation errors
-		_, err = gh.CreatePullRequest(
-			ctx,
-			owner,
+	// Create pull request.
+	pr, err := gh.CreatePullRequest(
+		ctx,
+		owner,
+		repoName,
+		*defaultBranch.Name,
+		pullRequestBranch,
+		pullRequestTitle,
+		pullRequestDescription,
+	)
+	if err != nil {
+		return "", fmt.Errorf(
+			"creating pull request for %s: %w",
 			repoName,
-			*defaultBranch.Name,
-			pullRequestBranch,
-			pullRequestTitle,
-			pullRequestDescription,
+			err,
 		)
-		if err != nil {
-			return fmt.Errorf(
-				"creating pull request for %s: %w",
-				repoName,
-				err,
-			)
-		}
 	}
 
-	return nil
+	return pr.GetHTMLURL(), nil
 }
@@ -0,0 +1,371 @@
+// Copyright 2022 OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package install
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	gogh "github.com/google/go-github/v46/github"
+
+	"github.com/ossf/scorecard-action/install/github"
+	"github.com/ossf/scorecard-action/install/options"
+)
+
+// fakeGitHub serves the repositories of the foo owner. Only GET requests are
+// served, so any mutating call fails the test.
+func fakeGitHub(t *testing.T) *github.Client {
+	t.Helper()
+	mux := http.NewServeMux()
+	repo := func(name string, archived, push bool) {
+		mux.HandleFunc("/repos/foo/"+name, func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprintf(w, `{"name":%q,"default_branch":"main","archived":%t,"permissions":{"pull":true,"push":%t}}`,
+				name, archived, push)
+		})
+		mux.HandleFunc("/repos/foo/"+name+"/branches/main", func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"name":"main","commit":{"sha":"68bc59901773ab4c051dfcea0cc4201a1567ab32"}}`)) //nolint:errcheck
+		})
+	}
+	repo("new", false, true)
+	repo("installed", false, true)
+	repo("pending", false, true)
+	repo("archived", true, true)
+	repo("read-only", false, false)
+	repo("contents-error", false, true)
+	repo("branch-error", false, true)
+	mux.HandleFunc("/repos/foo/contents-error/contents/.github/workflows/scorecards.yml", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/repos/foo/branch-error/branches/"+pullRequestBranch, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+	mux.HandleFunc("/repos/foo/forbidden", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"Must have admin rights to Repository."}`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/repos/foo/rate-limited", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-RateLimit-Limit", "5000")
+		w.Header().Set("X-RateLimit-Remaining", "0")
+		w.WriteHeader(http.StatusForbidden)
+		w.Write([]byte(`{"message":"API rate limit exceeded"}`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/repos/foo/installed/contents/.github/workflows/scorecards.yml", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"type":"file","name":"scorecards.yml","path":".github/workflows/scorecards.yml"}`)) //nolint:errcheck
+	})
+	// foo is an organization, bar a user and me the authenticated user.
+	mux.HandleFunc("/users/foo", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"login":"foo","type":"Organization"}`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/users/bar", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"login":"bar","type":"User"}`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/users/me", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"login":"me","type":"User"}`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/user", func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"login":"me","type":"User"}`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/users/bar/repos", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("type"); got != "owner" {
+			t.Errorf("type = %q, want owner", got)
+		}
+		w.Write([]byte(`[{"name":"personal","visibility":"public"}]`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/user/repos", func(w http.ResponseWriter, r *http.Request) {
+		if got := r.URL.Query().Get("affiliation"); got != "owner" {
+			t.Errorf("affiliation = %q, want owner", got)
+		}
+		w.Write([]byte(`[{"name":"mine","visibility":"public"},{"name":"secret","private":true}]`)) //nolint:errcheck
+	})
+
+	// The repositories of the organization, in two pages.
+	mux.HandleFunc("/orgs/foo/repos", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("page") == "2" {
+			w.Write([]byte(`[
+				{"name":"fork","fork":true,"visibility":"public","language":"Go"},
+				{"name":"internal","visibility":"internal","language":"Python","topics":["tools"]}
+			]`)) //nolint:errcheck
+			return
+		}
+		if got := r.URL.Query().Get("per_page"); got != "100" {
+			t.Errorf("per_page = %q, want 100", got)
+		}
+		w.Header().Set("Link", fmt.Sprintf(`<http://%s/orgs/foo/repos?page=2&per_page=100>; rel="next"`, r.Host))
+		w.Write([]byte(`[
+			{"name":"new","visibility":"public","language":"Go","topics":["security","tools"]},
+			{"name":"archived","archived":true,"visibility":"public","language":"Go"},
+			{"name":"private","private":true,"language":"go"}
+		]`)) //nolint:errcheck
+	})
+	mux.HandleFunc("/repos/foo/pending/branches/"+pullRequestBranch, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"` + pullRequestBranch + `"}`)) //nolint:errcheck
+	})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			t.Errorf("unexpected %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		mux.ServeHTTP(w, r)
+	}))
+	t.Cleanup(server.Close)
+
+	c := gogh.NewClient(nil)
+	baseURL, err := url.Parse(server.URL + "/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	c.BaseURL = baseURL
+	return &github.Client{Client: c}
+}
+
+func TestResolveRepo(t *testing.T) {
+	t.Parallel()
+	gh := fakeGitHub(t)
+	tests := []struct {
+		repo     string
+		want     string
+		wantKind string
+	}{
+		{repo: "new", want: StatusWouldCreatePR},
+		{repo: "installed", want: StatusWorkflowPresent},
+		{repo: "pending", want: StatusBranchExists},
+		{repo: "archived", want: StatusArchived},
+		{repo: "read-only", want: StatusNoAccess},
+		{repo: "missing", wantKind: ErrorNotFound},
+		{repo: "forbidden", wantKind: ErrorPermission},
+		{repo: "rate-limited", wantKind: ErrorRateLimit},
+		{repo: "contents-error", wantKind: ErrorOther},
+		{repo: "branch-error", wantKind: ErrorOther},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.repo, func(t *testing.T) {
+			t.Parallel()
+			plan, err := resolveRepo(context.Background(), gh, "foo", tt.repo)
+			if tt.wantKind != "" {
+				if got := errorKind(err); err == nil || got != tt.wantKind {
+					t.Fatalf("resolveRepo() error = %v, want %s", err, tt.wantKind)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveRepo(): %v", err)
+			}
+			if plan.status != tt.want {
+				t.Errorf("resolveRepo() status = %s, want %s", plan.status, tt.want)
+			}
+			if (plan.defaultBranch != nil) != (tt.want == StatusWouldCreatePR) {
+				t.Errorf("resolveRepo() default branch = %v", plan.defaultBranch)
+			}
+		})
+	}
+}
+
+func TestProcessRepoDryRun(t *testing.T) {
+	t.Parallel()
+	gh := fakeGitHub(t)
+	path := filepath.Join(t.TempDir(), "scorecards.yml")
+	if err := os.WriteFile(path, []byte(testWorkflow), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := parseWorkflow(path)
+	if err != nil {
+		t.Fatalf("parseWorkflow(): %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		repo     string
+		want     string
+		wantKind string
+		opts     options.Options
+	}{
+		{
+			name: "new",
+			repo: "new",
+			opts: options.Options{ActionSHA: "abc123"},
+			want: StatusWouldCreatePR,
+		},
+		{
+			name: "installed",
+			repo: "installed",
+			opts: options.Options{ActionSHA: "abc123"},
+			want: StatusWorkflowPresent,
+		},
+		{
+			name:     "template error",
+			repo:     "new",
+			want:     StatusFailed,
+			wantKind: ErrorOther,
+		},
+		{
+			name:     "rate limited",
+			repo:     "rate-limited",
+			opts:     options.Options{ActionSHA: "abc123"},
+			want:     StatusFailed,
+			wantKind: ErrorRateLimit,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			tt.opts.Owner = "foo"
+			tt.opts.DryRun = true
+			got := processRepo(context.Background(), gh, &tt.opts, tt.repo, tmpl)
+			if got.Status != tt.want || got.ErrorKind != tt.wantKind {
+				t.Errorf("processRepo() = %+v, want status %s, error kind %q", got, tt.want, tt.wantKind)
+			}
+			if got.PullRequestURL != "" {
+				t.Errorf("processRepo() opened a pull request in a dry run: %s", got.PullRequestURL)
+			}
+		})
+	}
+}
+
+func TestListRepositories(t *testing.T) {
+	t.Parallel()
+	gh := fakeGitHub(t)
+	tests := []struct {
+		name string
+		opts options.Options
+		want []string
+	}{
+		{
+			name: "defaults",
+			opts: options.Options{Visibility: options.VisibilityAll},
+			want: []string{"new", "private", "fork", "internal"},
+		},
+		{
+			name: "archived",
+			opts: options.Options{IncludeArchived: true},
+			want: []string{"new", "archived", "private", "fork", "internal"},
+		},
+		{
+			name: "no forks",
+			opts: options.Options{ExcludeForks: true},
+			want: []string{"new", "private", "internal"},
+		},
+		{
+			name: "private",
+			opts: options.Options{Visibility: options.VisibilityPrivate},
+			want: []string{"private"},
+		},
+		{
+			name: "internal",
+			opts: options.Options{Visibility: options.VisibilityInternal},
+			want: []string{"internal"},
+		},
+		{
+			name: "topics",
+			opts: options.Options{Topics: []string{"Tools"}},
+			want: []string{"new", "internal"},
+		},
+		{
+			name: "languages",
+			opts: options.Options{Languages: []string{"Go", "Rust"}},
+			want: []string{"new", "private", "fork"},
+		},
+		{
+			name: "user",
+			opts: options.Options{Owner: "bar"},
+			want: []string{"personal"},
+		},
+		{
+			name: "authenticated user",
+			opts: options.Options{Owner: "me"},
+			want: []string{"mine", "secret"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if tt.opts.Owner == "" {
+				tt.opts.Owner = "foo"
+			}
+			got, err := listRepositories(context.Background(), gh, &tt.opts)
+			if err != nil {
+				t.Fatalf("listRepositories(): %v", err)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("repositories: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	t.Parallel()
+	var r Report
+	r.add(RepoResult{Name: "new", Status: StatusCreatedPR, PullRequestURL: "https://github.com/foo/new/pull/1"})
+	r.add(RepoResult{Name: "archived", Status: StatusArchived})
+	r.add(failedResult("broken", errors.New("getting repository: boom")))
+
+	want := map[string]int{StatusCreatedPR: 1, StatusArchived: 1, StatusFailed: 1}
+	if diff := cmp.Diff(want, r.Summary); diff != "" {
+		t.Errorf("summary: -want, +got:\n%s", diff)
+	}
+	wantFailed := RepoResult{Name: "broken", Status: StatusFailed, ErrorKind: ErrorOther, Error: "getting repository: boom"}
+	if diff := cmp.Diff(wantFailed, r.Repositories[2]); diff != "" {
+		t.Errorf("failed repository: -want, +got:\n%s", diff)
+	}
+	if !r.Failed() {
+		t.Error("Failed() = false, want true")
+	}
+}
+
+func TestErrorKind(t *testing.T) {
+	t.Parallel()
+	responseErr := func(code int) error {
+		return fmt.Errorf("creating branch: %w", &gogh.ErrorResponse{Response: &http.Response{StatusCode: code}})
+	}
+	tests := []struct {
+		err  error
+		name string
+		want string
+	}{
+		{name: "forbidden", err: responseErr(http.StatusForbidden), want: ErrorPermission},
+		{name: "unauthorized", err: responseErr(http.StatusUnauthorized), want: ErrorPermission},
+		{name: "not found", err: responseErr(http.StatusNotFound), want: ErrorNotFound},
+		{name: "conflict", err: responseErr(http.StatusConflict), want: ErrorConflict},
+		{name: "unprocessable", err: responseErr(http.StatusUnprocessableEntity), want: ErrorConflict},
+		{name: "too many requests", err: responseErr(http.StatusTooManyRequests), want: ErrorRateLimit},
+		{name: "rate limit", err: &gogh.RateLimitError{}, want: ErrorRateLimit},
+		{name: "secondary rate limit", err: &gogh.AbuseRateLimitError{}, want: ErrorRateLimit},
+		{name: "server error", err: responseErr(http.StatusInternalServerError), want: ErrorOther},
+		{name: "other", err: errors.New("boom"), want: ErrorOther},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if got := errorKind(tt.err); got != tt.want {
+				t.Errorf("errorKind() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
@@ -26,6 +26,40 @@ const (
 
 	// FlagRepos is the flag name for specifying a set of repositories.
 	FlagRepos = "repos"
+
+	// FlagWorkflow is the flag name for specifying the workflow template.
+	FlagWorkflow = "workflow"
+
+	// FlagCron is the flag name for specifying the workflow schedule.
+	FlagCron = "cron"
+
+	// FlagPublishResults is the flag name for setting publish_results in the
+	// workflow.
+	FlagPublishResults = "publish-results"
+
+	// FlagActionSHA is the flag name for specifying the pinned action commit.
+	FlagActionSHA = "action-sha"
+
+	// FlagVisibility is the flag name for filtering repositories by visibility.
+	FlagVisibility = "visibility"
+
+	// FlagIncludeArchived is the flag name for listing archived repositories.
+	FlagIncludeArchived = "include-archived"
+
+	// FlagExcludeForks is the flag name for skipping forked repositories.
+	FlagExcludeForks = "exclude-forks"
+
+	// FlagTopics is the flag name for filtering repositories by topic.
+	FlagTopics = "topics"
+
+	// FlagLanguages is the flag name for filtering repositories by language.
+	FlagLanguages = "languages"
+
+	// FlagDryRun is the flag name for reporting changes without making them.
+	FlagDryRun = "dry-run"
+
+	// FlagOutput is the flag name for specifying the report format.
+	FlagOutput = "output"
 )
 
 // Command is an interface for handling options for command-line utilities.
@@ -49,4 +83,81 @@ func (o *Options) AddFlags(cmd *cobra.Command) {
 		o.Repositories,
 		"repositories to install the scorecard action on",
 	)
+
+	cmd.Flags().StringVar(
+		&o.ConfigPath,
+		FlagWorkflow,
+		o.ConfigPath,
+		"path of the workflow template to install",
+	)
+
+	cmd.Flags().StringVar(
+		&o.Cron,
+		FlagCron,
+		o.Cron,
+		"cron schedule of the workflow, the Cron template variable",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.PublishResults,
+		FlagPublishResults,
+		o.PublishResults,
+		"whether the workflow publishes its results, the PublishResults template variable",
+	)
+
+	cmd.Flags().StringVar(
+		&o.ActionSHA,
+		FlagActionSHA,
+		o.ActionSHA,
+		"commit SHA of ossf/scorecard-action to pin, the ActionSHA template variable",
+	)
+
+	cmd.Flags().StringVar(
+		&o.Visibility,
+		FlagVisibility,
+		o.Visibility,
+		"visibility of the listed repositories: all, public, private or internal",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.IncludeArchived,
+		FlagIncludeArchived,
+		o.IncludeArchived,
+		"include archived repositories when listing",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.ExcludeForks,
+		FlagExcludeForks,
+		o.ExcludeForks,
+		"skip forked repositories when listing",
+	)
+
+	cmd.Flags().StringSliceVar(
+		&o.Topics,
+		FlagTopics,
+		o.Topics,
+		"only list repositories with any of these topics",
+	)
+
+	cmd.Flags().StringSliceVar(
+		&o.Languages,
+		FlagLanguages,
+		o.Languages,
+		"only list repositories with any of these primary languages",
+	)
+
+	cmd.Flags().BoolVar(
+		&o.DryRun,
+		FlagDryRun,
+		o.DryRun,
+		"report what would change in each repository, without changing anything",
+	)
+
+	cmd.Flags().StringVar(
+		&o.Output,
+		FlagOutput,
+		o.Output,
+		"format of the report: table or json (default json with --dry-run, else table)",
+	)
 }
@@ -19,32 +19,97 @@ package options
 
 import (
 	"errors"
+	"fmt"
 	"path/filepath"
+	"regexp"
+	"strings"
 )
 
 const (
 	configDir      = "starter-workflows/code-scanning"
 	configFilename = "scorecards.yml"
+
+	// DefaultCron is the default schedule of the workflow, weekly.
+	DefaultCron = "20 7 * * 2"
+)
+
+// Repository visibilities.
+const (
+	VisibilityAll      = "all"
+	VisibilityPublic   = "public"
+	VisibilityPrivate  = "private"
+	VisibilityInternal = "internal"
+)
+
+// Report output formats.
+const (
+	OutputTable = "table"
+	OutputJSON  = "json"
 )
 
-var errOwnerNotSpecified = errors.New("owner not specified")
+var (
+	errOwnerNotSpecified = errors.New("owner not specified")
+	errInvalidOutput     = errors.New("invalid output format")
+	errInvalidVisibility = errors.New("invalid visibility")
+	errInvalidCron       = errors.New("invalid cron schedule")
+	errInvalidActionSHA  = errors.New("invalid action SHA")
+
+	actionSHAPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+)
 
 // Options are installation options for the scorecard action.
 type Options struct {
-	// Scorecard GitHub Action configuration path
+	// ConfigPath is the path of the workflow template, see the --workflow flag.
 	ConfigPath string
 
+	// Cron is the schedule of the workflow, the Cron template variable.
+	Cron string
+
+	// ActionSHA is the commit of ossf/scorecard-action the workflow is pinned
+	// to, the ActionSHA template variable.
+	ActionSHA string
+
 	// GitHub org/repo owner
 	Owner string
 
 	// Repositories
 	Repositories []string
+
+	// Visibility restricts the listed repositories to VisibilityPublic,
+	// VisibilityPrivate or VisibilityInternal ones.
+	Visibility string
+
+	// Topics restricts the listed repositories to those with any of the topics.
+	Topics []string
+
+	// Languages restricts the listed repositories to those with any of the
+	// primary languages.
+	Languages []string
+
+	// Output is the format of the report, OutputTable or OutputJSON. It
+	// defaults to JSON for dry runs and to a table otherwise.
+	Output string
+
+	// DryRun reports what would change in each repository without changing it.
+	DryRun bool
+
+	// PublishResults is the PublishResults template variable.
+	PublishResults bool
+
+	// IncludeArchived lists archived repositories, which are skipped by default.
+	IncludeArchived bool
+
+	// ExcludeForks skips forked repositories when listing.
+	ExcludeForks bool
 }
 
 // New creates a new instance of installation options.
 func New() *Options {
 	opts := &Options{}
 	opts.ConfigPath = GetConfigPath()
+	opts.Visibility = VisibilityAll
+	opts.Cron = DefaultCron
+	opts.PublishResults = true
 	return opts
 }
 
@@ -53,12 +118,40 @@ func (o *Options) Validate() error {
 	if o.Owner == "" {
 		return errOwnerNotSpecified
 	}
+	switch o.Visibility {
+	case "", VisibilityAll, VisibilityPublic, VisibilityPrivate, VisibilityInternal:
+	default:
+		return fmt.Errorf("%w: %s", errInvalidVisibility, o.Visibility)
+	}
+	if len(strings.Fields(o.Cron)) != 5 {
+		return fmt.Errorf("%w: %q", errInvalidCron, o.Cron)
+	}
+	if o.ActionSHA != "" && !actionSHAPattern.MatchString(o.ActionSHA) {
+		return fmt.Errorf("%w: %s", errInvalidActionSHA, o.ActionSHA)
+	}
+	switch o.Output {
+	case "", OutputTable, OutputJSON:
+	default:
+		return fmt.Errorf("%w: %s", errInvalidOutput, o.Output)
+	}
 
 	return nil
 }
 
-// GetConfigPath returns the local path for the scorecard action config file.
-// TODO: Consider making this configurable.
+// OutputFormat returns the format of the report.
+func (o *Options) OutputFormat() string {
+	switch {
+	case o.Output != "":
+		return o.Output
+	case o.DryRun:
+		return OutputJSON
+	default:
+		return OutputTable
+	}
+}
+
+// GetConfigPath returns the default local path for the scorecard workflow
+// template.
 func GetConfigPath() string {
 	return filepath.Join(configDir, configFilename)
 }
@@ -0,0 +1,118 @@
+// Copyright 2022 OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package install
+
+import (
+	"errors"
+	"net/http"
+
+	gogh "github.com/google/go-github/v46/github"
+)
+
+// Repository statuses.
+const (
+	// StatusWorkflowPresent means the repository already has a scorecard workflow.
+	StatusWorkflowPresent = "workflow-present"
+	// StatusBranchExists means the installation branch already exists, e.g.
+	// from an earlier run.
+	StatusBranchExists = "install-branch-exists"
+	// StatusArchived means the repository is archived, so it can't be changed.
+	StatusArchived = "archived"
+	// StatusNoAccess means the token can't push to the repository.
+	StatusNoAccess = "no-access"
+	// StatusWouldCreatePR means a pull request adding the workflow would be
+	// created.
+	StatusWouldCreatePR = "would-create-pr"
+	// StatusCreatedPR means a pull request adding the workflow was created.
+	StatusCreatedPR = "created-pr"
+	// StatusFailed means the repository couldn't be resolved or changed.
+	StatusFailed = "failed"
+)
+
+// Error kinds of failed repositories.
+const (
+	ErrorPermission = "permission"
+	ErrorNotFound   = "not-found"
+	ErrorConflict   = "conflict"
+	ErrorRateLimit  = "rate-limit"
+	ErrorOther      = "other"
+)
+
+// RepoResult is the outcome for a single repository.
+type RepoResult struct {
+	Name           string `json:"name"`
+	Status         string `json:"status"`
+	PullRequestURL string `json:"pullRequestURL,omitempty"`
+	// ErrorKind categorizes Error, e.g. ErrorPermission.
+	ErrorKind string `json:"errorKind,omitempty"`
+	Error     string `json:"error,omitempty"`
+}
+
+// Report lists the outcome for every repository the installer processed.
+type Report struct {
+	Summary      map[string]int `json:"summary"`
+	Owner        string         `json:"owner"`
+	Repositories []RepoResult   `json:"repositories"`
+	DryRun       bool           `json:"dryRun"`
+}
+
+// Failed reports whether any repository failed.
+func (r *Report) Failed() bool {
+	return r.Summary[StatusFailed] > 0
+}
+
+func (r *Report) add(result RepoResult) {
+	if r.Summary == nil {
+		r.Summary = map[string]int{}
+	}
+	r.Summary[result.Status]++
+	r.Repositories = append(r.Repositories, result)
+}
+
+func failedResult(name string, err error) RepoResult {
+	return RepoResult{
+		Name:      name,
+		Status:    StatusFailed,
+		ErrorKind: errorKind(err),
+		Error:     err.Error(),
+	}
+}
+
+// errorKind categorizes an error returned by the GitHub API.
+func errorKind(err error) string {
+	var rateLimitErr *gogh.RateLimitError
+	var abuseErr *gogh.AbuseRateLimitError
+	if errors.As(err, &rateLimitErr) || errors.As(err, &abuseErr) {
+		return ErrorRateLimit
+	}
+	var respErr *gogh.ErrorResponse
+	if !errors.As(err, &respErr) || respErr.Response == nil {
+		return ErrorOther
+	}
+	switch respErr.Response.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrorPermission
+	case http.StatusNotFound:
+		return ErrorNotFound
+	case http.StatusConflict, http.StatusUnprocessableEntity:
+		return ErrorConflict
+	case http.StatusTooManyRequests:
+		return ErrorRateLimit
+	default:
+		return ErrorOther
+	}
+}
@@ -0,0 +1,150 @@
+// Copyright 2022 OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package install
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/ossf/scorecard-action/install/options"
+)
+
+// Workflow templates use [[ ]] as delimiters, since {{ }} would clash with
+// GitHub Actions expressions like ${{ github.ref }}. Bash tests like
+// [[ -f x ]] have to be escaped as [[ "[[" ]] -f x ]] instead.
+const (
+	workflowLeftDelim  = "[["
+	workflowRightDelim = "]]"
+	// defaultBranchPlaceholder is substituted with the default branch, as GitHub
+	// does for starter workflows.
+	defaultBranchPlaceholder = "$default-branch"
+)
+
+var errUnusedVariable = errors.New("the workflow template doesn't use the variable")
+
+// parseWorkflow reads the workflow template at path. Templates fail to render
+// when they use a variable that isn't set, such as ActionSHA without the
+// --action-sha flag.
+func parseWorkflow(path string) (*template.Template, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading scorecard workflow file: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).
+		Delims(workflowLeftDelim, workflowRightDelim).
+		Option("missingkey=error").
+		Parse(string(contents))
+	if err != nil {
+		return nil, fmt.Errorf("parsing scorecard workflow template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// renderWorkflow renders the workflow template for a repository.
+func renderWorkflow(
+	tmpl *template.Template,
+	o *options.Options,
+	repoName, defaultBranch string,
+) ([]byte, error) {
+	vars := map[string]any{
+		"Owner":          o.Owner,
+		"Repository":     repoName,
+		"DefaultBranch":  defaultBranch,
+		"Cron":           o.Cron,
+		"PublishResults": o.PublishResults,
+	}
+	if o.ActionSHA != "" {
+		vars["ActionSHA"] = o.ActionSHA
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, vars); err != nil {
+		return nil, fmt.Errorf("rendering scorecard workflow for %s: %w", repoName, err)
+	}
+	return bytes.ReplaceAll(buf.Bytes(), []byte(defaultBranchPlaceholder), []byte(defaultBranch)), nil
+}
+
+// checkWorkflowVariables fails when a flag sets a template variable the
+// workflow template doesn't use, e.g. --cron with the upstream starter
+// workflow, which would otherwise be ignored silently.
+func checkWorkflowVariables(tmpl *template.Template, o *options.Options) error {
+	used := map[string]bool{}
+	for _, t := range tmpl.Templates() {
+		if t.Tree != nil {
+			templateFields(t.Tree.Root, used)
+		}
+	}
+	vars := []struct {
+		flag, name string
+		set        bool
+	}{
+		{options.FlagCron, "Cron", o.Cron != options.DefaultCron},
+		{options.FlagPublishResults, "PublishResults", !o.PublishResults},
+		{options.FlagActionSHA, "ActionSHA", o.ActionSHA != ""},
+	}
+	for _, v := range vars {
+		if v.set && !used[v.name] {
+			return fmt.Errorf("%w: --%s sets %s, which %s doesn't use", errUnusedVariable, v.flag, v.name, tmpl.Name())
+		}
+	}
+	return nil
+}
+
+// templateFields records the names of the fields the template node uses.
+func templateFields(node parse.Node, used map[string]bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Nodes {
+			templateFields(c, used)
+		}
+	case *parse.ActionNode:
+		templateFields(n.Pipe, used)
+	case *parse.PipeNode:
+		if n == nil {
+			return
+		}
+		for _, c := range n.Cmds {
+			templateFields(c, used)
+		}
+	case *parse.CommandNode:
+		for _, a := range n.Args {
+			templateFields(a, used)
+		}
+	case *parse.FieldNode:
+		used[n.Ident[0]] = true
+	case *parse.IfNode:
+		templateFields(&n.BranchNode, used)
+	case *parse.RangeNode:
+		templateFields(&n.BranchNode, used)
+	case *parse.WithNode:
+		templateFields(&n.BranchNode, used)
+	case *parse.BranchNode:
+		templateFields(n.Pipe, used)
+		templateFields(n.List, used)
+		templateFields(n.ElseList, used)
+	case *parse.TemplateNode:
+		templateFields(n.Pipe, used)
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2022 OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package install
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"text/template"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard-action/install/options"
+)
+
+const testWorkflow = `on:
+  branch_protection_rule:
+  schedule:
+    - cron: '[[ .Cron ]]'
+  push:
+    branches: [ "[[ .DefaultBranch ]]" ]
+jobs:
+  analysis:
+    if: github.repository == '[[ .Owner ]]/[[ .Repository ]]'
+    steps:
+      - uses: ossf/scorecard-action@[[ .ActionSHA ]]
+        with:
+          repo_token: ${{ secrets.SCORECARD_TOKEN }}
+          publish_results: [[ .PublishResults ]]
+`
+
+func TestRenderWorkflow(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "scorecards.yml")
+	if err := os.WriteFile(path, []byte(testWorkflow), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := parseWorkflow(path)
+	if err != nil {
+		t.Fatalf("parseWorkflow(): %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		opts    options.Options
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "all variables",
+			opts: options.Options{
+				Owner:     "foo",
+				Cron:      "30 1 * * 6",
+				ActionSHA: "4eaacf0543bb3f2c246792bd56e8cdeffafb205a",
+			},
+			want: `on:
+  branch_protection_rule:
+  schedule:
+    - cron: '30 1 * * 6'
+  push:
+    branches: [ "trunk" ]
+jobs:
+  analysis:
+    if: github.repository == 'foo/bar'
+    steps:
+      - uses: ossf/scorecard-action@4eaacf0543bb3f2c246792bd56e8cdeffafb205a
+        with:
+          repo_token: ${{ secrets.SCORECARD_TOKEN }}
+          publish_results: false
+`,
+		},
+		{
+			name:    "action SHA not set",
+			opts:    options.Options{Owner: "foo", Cron: options.DefaultCron, PublishResults: true},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := renderWorkflow(tmpl, &tt.opts, "bar", "trunk")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("renderWorkflow() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("workflow: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRenderWorkflowStarter(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "scorecards.yml")
+	starter := `on:
+  push:
+    branches: [ $default-branch ]
+jobs:
+  analysis:
+    steps:
+      - run: if [[ "[[" ]] -f results.sarif ]]; then cat results.sarif; fi
+`
+	if err := os.WriteFile(path, []byte(starter), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := parseWorkflow(path)
+	if err != nil {
+		t.Fatalf("parseWorkflow(): %v", err)
+	}
+	got, err := renderWorkflow(tmpl, &options.Options{Owner: "foo"}, "bar", "trunk")
+	if err != nil {
+		t.Fatalf("renderWorkflow(): %v", err)
+	}
+	want := `on:
+  push:
+    branches: [ trunk ]
+jobs:
+  analysis:
+    steps:
+      - run: if [[ -f results.sarif ]]; then cat results.sarif; fi
+`
+	if diff := cmp.Diff(want, string(got)); diff != "" {
+		t.Errorf("workflow: -want, +got:\n%s", diff)
+	}
+}
+
+func TestParseWorkflowError(t *testing.T) {
+	t.Parallel()
+	path := filepath.Join(t.TempDir(), "scorecards.yml")
+	if err := os.WriteFile(path, []byte("branches: [ [[ .DefaultBranch ]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := parseWorkflow(path); err == nil {
+		t.Error("parseWorkflow() succeeded on an unterminated action")
+	}
+}
+
+func TestCheckWorkflowVariables(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	parse := func(name, contents string) *template.Template {
+		t.Helper()
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(contents), 0o600); err != nil {
+			t.Fatal(err)
+		}
+		tmpl, err := parseWorkflow(path)
+		if err != nil {
+			t.Fatalf("parseWorkflow(): %v", err)
+		}
+		return tmpl
+	}
+	templated := parse("templated.yml", testWorkflow)
+	starter := parse("starter.yml", "on:\n  push:\n    branches: [ $default-branch ]\n")
+	conditional := parse("conditional.yml", "[[ if .ActionSHA ]]uses: ossf/scorecard-action@[[ .ActionSHA ]][[ end ]]\n")
+
+	defaults := options.Options{Cron: options.DefaultCron, PublishResults: true}
+	tests := []struct {
+		tmpl    *template.Template
+		name    string
+		opts    options.Options
+		wantErr bool
+	}{
+		{name: "starter with defaults", tmpl: starter, opts: defaults},
+		{name: "templated with flags", tmpl: templated, opts: options.Options{Cron: "30 1 * * 6", ActionSHA: "abc"}},
+		{
+			name:    "starter with cron",
+			tmpl:    starter,
+			opts:    options.Options{Cron: "30 1 * * 6", PublishResults: true},
+			wantErr: true,
+		},
+		{
+			name:    "starter without publishing",
+			tmpl:    starter,
+			opts:    options.Options{Cron: options.DefaultCron},
+			wantErr: true,
+		},
+		{
+			name: "action SHA in a condition",
+			tmpl: conditional,
+			opts: options.Options{Cron: options.DefaultCron, PublishResults: true, ActionSHA: "abc"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := checkWorkflowVariables(tt.tmpl, &tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("checkWorkflowVariables() error = %v, wantErr %t", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ossf/scorecard/v5/checker"
+	"github.com/ossf/scorecard/v5/docs/checks"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+// codeQualityIssue is an issue of a GitLab Code Quality report.
+// https://docs.gitlab.com/ee/ci/testing/code_quality.html#implement-a-custom-tool
+type codeQualityIssue struct {
+	Description string              `json:"description"`
+	CheckName   string              `json:"check_name"`
+	Fingerprint string              `json:"fingerprint"`
+	Severity    string              `json:"severity"`
+	Location    codeQualityLocation `json:"location"`
+}
+
+type codeQualityLocation struct {
+	Path  string           `json:"path"`
+	Lines codeQualityLines `json:"lines"`
+}
+
+type codeQualityLines struct {
+	Begin uint `json:"begin"`
+}
+
+// codeQualitySeverity maps the risk of a check to a Code Quality severity.
+var codeQualitySeverity = map[string]string{
+	"Critical": "critical",
+	"High":     "major",
+	"Medium":   "minor",
+	"Low":      "info",
+}
+
+// asCodeQuality writes the result as a GitLab Code Quality report, with an
+// issue for every check that did not get the maximum score. Skipped checks are
+// info issues, other inconclusive checks are left out.
+func asCodeQuality(result *scorecard.Result, writer io.Writer, docs checks.Doc) error {
+	issues := []codeQualityIssue{}
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		if IsSkipped(check) {
+			issues = append(issues, codeQualityIssue{
+				Description: fmt.Sprintf("%s: %s", check.Name, check.Reason),
+				CheckName:   check.Name,
+				Fingerprint: codeQualityFingerprint(result, check),
+				Severity:    "info",
+				Location:    codeQualityLocation{Path: ".", Lines: codeQualityLines{Begin: 1}},
+			})
+			continue
+		}
+		if check.Score == checker.InconclusiveResultScore || check.Score >= checker.MaxResultScore {
+			continue
+		}
+
+		severity := "info"
+		if doc, err := docs.GetCheck(check.Name); err == nil {
+			if s, ok := codeQualitySeverity[doc.GetRisk()]; ok {
+				severity = s
+			}
+		}
+		location := codeQualityLocation{Path: ".", Lines: codeQualityLines{Begin: 1}}
+		for _, detail := range check.Details {
+			if detail.Msg.Path != "" {
+				location.Path = detail.Msg.Path
+				location.Lines.Begin = max(detail.Msg.Offset, 1)
+				break
+			}
+		}
+
+		issues = append(issues, codeQualityIssue{
+			Description: fmt.Sprintf("%s: %s (score %d/%d)",
+				check.Name, check.Reason, check.Score, checker.MaxResultScore),
+			CheckName:   check.Name,
+			Fingerprint: codeQualityFingerprint(result, check),
+			Severity:    severity,
+			Location:    location,
+		})
+	}
+
+	if err := json.NewEncoder(writer).Encode(issues); err != nil {
+		return fmt.Errorf("writing code quality report: %w", err)
+	}
+	return nil
+}
+
+// codeQualityFingerprint identifies the issue of a check across runs.
+func codeQualityFingerprint(result *scorecard.Result, check *checker.CheckResult) string {
+	fingerprint := sha256.Sum256([]byte(result.Repo.Name + "/" + check.Name))
+	return hex.EncodeToString(fingerprint[:])
+}
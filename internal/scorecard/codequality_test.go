@@ -0,0 +1,117 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
+	"github.com/ossf/scorecard/v5/checker"
+	"github.com/ossf/scorecard/v5/docs/checks"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+func TestAsCodeQuality(t *testing.T) {
+	t.Parallel()
+	docs, err := checks.Read()
+	if err != nil {
+		t.Fatalf("read check docs: %v", err)
+	}
+	tests := []struct {
+		name   string
+		checks []checker.CheckResult
+		want   []codeQualityIssue
+	}{
+		{
+			name: "perfect and inconclusive checks are left out",
+			checks: []checker.CheckResult{
+				{Name: "Binary-Artifacts", Score: checker.MaxResultScore},
+				{Name: "Fuzzing", Score: checker.InconclusiveResultScore},
+			},
+			want: []codeQualityIssue{},
+		},
+		{
+			name: "issue located at the first detail with a path",
+			checks: []checker.CheckResult{
+				{
+					Name:   "Dangerous-Workflow",
+					Score:  0,
+					Reason: "dangerous workflow patterns detected",
+					Details: []checker.CheckDetail{
+						{Type: checker.DetailWarn, Msg: checker.LogMessage{Text: "no path"}},
+						{Type: checker.DetailWarn, Msg: checker.LogMessage{Path: ".github/workflows/ci.yml", Offset: 12}},
+					},
+				},
+			},
+			want: []codeQualityIssue{
+				{
+					Description: "Dangerous-Workflow: dangerous workflow patterns detected (score 0/10)",
+					CheckName:   "Dangerous-Workflow",
+					Severity:    "critical",
+					Location: codeQualityLocation{
+						Path:  ".github/workflows/ci.yml",
+						Lines: codeQualityLines{Begin: 12},
+					},
+				},
+			},
+		},
+		{
+			name: "issue without a path",
+			checks: []checker.CheckResult{
+				{Name: "Code-Review", Score: 4, Reason: "found 4/10 approved changesets"},
+			},
+			want: []codeQualityIssue{
+				{
+					Description: "Code-Review: found 4/10 approved changesets (score 4/10)",
+					CheckName:   "Code-Review",
+					Severity:    "major",
+					Location: codeQualityLocation{
+						Path:  ".",
+						Lines: codeQualityLines{Begin: 1},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := scorecard.Result{
+				Repo:   scorecard.RepoInfo{Name: "gitlab.com/foo/bar"},
+				Checks: tt.checks,
+			}
+			var buf bytes.Buffer
+			if err := asCodeQuality(&result, &buf, docs); err != nil {
+				t.Fatalf("asCodeQuality(): %v", err)
+			}
+			var got []codeQualityIssue
+			if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+				t.Fatalf("parsing report: %v", err)
+			}
+			for _, issue := range got {
+				if len(issue.Fingerprint) != 64 {
+					t.Errorf("fingerprint %q is not a sha256 hex digest", issue.Fingerprint)
+				}
+			}
+			if diff := cmp.Diff(tt.want, got, cmpopts.IgnoreFields(codeQualityIssue{}, "Fingerprint")); diff != "" {
+				t.Errorf("asCodeQuality(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
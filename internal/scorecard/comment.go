@@ -0,0 +1,92 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/ossf/scorecard-action/github"
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/docs/checks"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+// CommentMarker tags the pull request comment owned by the action, so later
+// runs update it instead of adding a new one.
+const CommentMarker = "<!-- ossf/scorecard-action: results -->"
+
+var errNoPullRequest = errors.New("pull request number unavailable")
+
+// PostComment creates or updates the Scorecard results comment on the pull
+// request that triggered the run. The delta report is optional.
+func PostComment(gh *github.Client, result *scorecard.Result, delta *DeltaReport, opts *options.Options) error {
+	if result == nil {
+		return errNoResult
+	}
+	if opts.PullRequestNumber == 0 {
+		return errNoPullRequest
+	}
+
+	docs, err := checks.Read()
+	if err != nil {
+		return fmt.Errorf("read check docs: %w", err)
+	}
+	var body strings.Builder
+	if err := writeComment(&body, result, delta, docs); err != nil {
+		return err
+	}
+
+	if _, err := gh.UpsertComment(
+		opts.GithubAPIURL,
+		opts.GithubRepository,
+		opts.PullRequestNumber,
+		CommentMarker,
+		body.String(),
+	); err != nil {
+		return fmt.Errorf("posting pull request comment: %w", err)
+	}
+	return nil
+}
+
+func writeComment(w io.Writer, result *scorecard.Result, delta *DeltaReport, docs checks.Doc) error {
+	if _, err := fmt.Fprintf(w, "%s\n", CommentMarker); err != nil {
+		return fmt.Errorf("writing comment: %w", err)
+	}
+	if err := asMarkdown(result, w, docs); err != nil {
+		return err
+	}
+	if delta != nil {
+		var sb strings.Builder
+		fmt.Fprintf(&sb, "\n### Changes compared to the default branch\n\n")
+		fmt.Fprintf(&sb, "Aggregate score: %s → %s\n\n", scoreToString(delta.BaseScore), scoreToString(delta.HeadScore))
+		changed := append(delta.Regressions(), delta.Improvements()...)
+		if len(changed) == 0 {
+			sb.WriteString("No check scores changed.\n")
+		} else {
+			sb.WriteString("| Check | Default branch | This PR | Change |\n")
+			sb.WriteString("| ----- | -------------- | ------- | ------ |\n")
+			for _, c := range changed {
+				fmt.Fprintf(&sb, "| %s | %d | %d | %+d |\n", c.Name, c.BaseScore, c.HeadScore, c.Change)
+			}
+		}
+		if _, err := io.WriteString(w, sb.String()); err != nil {
+			return fmt.Errorf("writing comment: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/ossf/scorecard/v5/checker"
+	"github.com/ossf/scorecard/v5/docs/checks"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+func TestWriteComment(t *testing.T) {
+	t.Parallel()
+	docs, err := checks.Read()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := scorecard.Result{
+		Repo: scorecard.RepoInfo{
+			Name: "github.com/foo/bar",
+		},
+		Checks: []checker.CheckResult{
+			{Name: "Code-Review", Score: 5, Reason: "Found 5/10 approved changesets"},
+		},
+	}
+	delta := DeltaReport{
+		Checks: []CheckDelta{
+			{Name: "Code-Review", Status: DeltaRegressed, BaseScore: 8, HeadScore: 5, Change: -3},
+			{Name: "Fuzzing", Status: DeltaUnchanged, BaseScore: 10, HeadScore: 10},
+		},
+	}
+	tests := []struct {
+		name  string
+		delta *DeltaReport
+		want  []string
+		skip  []string
+	}{
+		{
+			name: "results only",
+			want: []string{CommentMarker, "| Check | Score | Reason |", "Found 5/10 approved changesets"},
+			skip: []string{"Changes compared to the default branch"},
+		},
+		{
+			name:  "with delta",
+			delta: &delta,
+			want:  []string{CommentMarker, "Changes compared to the default branch", "| Code-Review | 8 | 5 | -3 |"},
+			skip:  []string{"| Fuzzing |"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var sb strings.Builder
+			if err := writeComment(&sb, &result, tt.delta, docs); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got := sb.String()
+			if !strings.HasPrefix(got, CommentMarker) {
+				t.Errorf("comment does not start with marker:\n%s", got)
+			}
+			for _, w := range tt.want {
+				if !strings.Contains(got, w) {
+					t.Errorf("comment missing %q:\n%s", w, got)
+				}
+			}
+			for _, s := range tt.skip {
+				if strings.Contains(got, s) {
+					t.Errorf("comment unexpectedly contains %q:\n%s", s, got)
+				}
+			}
+		})
+	}
+}
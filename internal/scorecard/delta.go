@@ -0,0 +1,256 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/checker"
+	"github.com/ossf/scorecard/v5/docs/checks"
+	scopts "github.com/ossf/scorecard/v5/options"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+// Check delta statuses.
+const (
+	DeltaRegressed    = "regressed"
+	DeltaImproved     = "improved"
+	DeltaUnchanged    = "unchanged"
+	DeltaAdded        = "added"
+	DeltaRemoved      = "removed"
+	DeltaInconclusive = "inconclusive"
+)
+
+// CheckDelta is the change in a single check's score between two results.
+type CheckDelta struct {
+	Name      string `json:"name"`
+	Status    string `json:"status"`
+	BaseScore int    `json:"baseScore"`
+	HeadScore int    `json:"headScore"`
+	Change    int    `json:"change"`
+}
+
+func (c CheckDelta) String() string {
+	switch c.Status {
+	case DeltaRegressed, DeltaImproved:
+		return fmt.Sprintf("%s %s from %d to %d (%+d)", c.Name, c.Status, c.BaseScore, c.HeadScore, c.Change)
+	default:
+		return fmt.Sprintf("%s %s", c.Name, c.Status)
+	}
+}
+
+// DeltaReport compares a result against a baseline result, typically a pull
+// request against its default branch.
+type DeltaReport struct {
+	Repo       string       `json:"repo"`
+	BaseCommit string       `json:"baseCommit"`
+	HeadCommit string       `json:"headCommit"`
+	Checks     []CheckDelta `json:"checks"`
+	BaseScore  float64      `json:"baseScore"`
+	HeadScore  float64      `json:"headScore"`
+}
+
+// Regressions returns the checks whose score went down.
+func (d *DeltaReport) Regressions() []CheckDelta {
+	return d.withStatus(DeltaRegressed)
+}
+
+// Improvements returns the checks whose score went up.
+func (d *DeltaReport) Improvements() []CheckDelta {
+	return d.withStatus(DeltaImproved)
+}
+
+func (d *DeltaReport) withStatus(status string) []CheckDelta {
+	var ret []CheckDelta
+	for _, c := range d.Checks {
+		if c.Status == status {
+			ret = append(ret, c)
+		}
+	}
+	return ret
+}
+
+// Compare builds a delta report of head against base. Checks are reported in
+// the order they appear in head, followed by any checks only present in base.
+func Compare(base, head *scorecard.Result, docs checks.Doc) (*DeltaReport, error) {
+	if base == nil || head == nil {
+		return nil, errNoResult
+	}
+	baseScore, err := base.GetAggregateScore(docs)
+	if err != nil {
+		return nil, fmt.Errorf("computing baseline aggregate score: %w", err)
+	}
+	headScore, err := head.GetAggregateScore(docs)
+	if err != nil {
+		return nil, fmt.Errorf("computing aggregate score: %w", err)
+	}
+
+	report := &DeltaReport{
+		Repo:       head.Repo.Name,
+		BaseCommit: base.Repo.CommitSHA,
+		HeadCommit: head.Repo.CommitSHA,
+		BaseScore:  baseScore,
+		HeadScore:  headScore,
+	}
+
+	baseChecks := make(map[string]int, len(base.Checks))
+	for i := range base.Checks {
+		baseChecks[base.Checks[i].Name] = base.Checks[i].Score
+	}
+	seen := make(map[string]bool, len(head.Checks))
+	for i := range head.Checks {
+		check := &head.Checks[i]
+		seen[check.Name] = true
+		// Skipped checks weren't run, there is nothing to compare.
+		if IsSkipped(check) {
+			continue
+		}
+		baseScore, ok := baseChecks[check.Name]
+		if !ok {
+			report.Checks = append(report.Checks, CheckDelta{
+				Name:      check.Name,
+				Status:    DeltaAdded,
+				BaseScore: checker.InconclusiveResultScore,
+				HeadScore: check.Score,
+			})
+			continue
+		}
+		report.Checks = append(report.Checks, checkDelta(check.Name, baseScore, check.Score))
+	}
+	for i := range base.Checks {
+		check := &base.Checks[i]
+		if seen[check.Name] {
+			continue
+		}
+		report.Checks = append(report.Checks, CheckDelta{
+			Name:      check.Name,
+			Status:    DeltaRemoved,
+			BaseScore: check.Score,
+			HeadScore: checker.InconclusiveResultScore,
+		})
+	}
+	return report, nil
+}
+
+func checkDelta(name string, base, head int) CheckDelta {
+	d := CheckDelta{
+		Name:      name,
+		BaseScore: base,
+		HeadScore: head,
+	}
+	if base == checker.InconclusiveResultScore || head == checker.InconclusiveResultScore {
+		d.Status = DeltaInconclusive
+		return d
+	}
+	d.Change = head - base
+	switch {
+	case d.Change < 0:
+		d.Status = DeltaRegressed
+	case d.Change > 0:
+		d.Status = DeltaImproved
+	default:
+		d.Status = DeltaUnchanged
+	}
+	return d
+}
+
+// CompareToBaseline compares the result of a pull request run against the
+// default branch and writes the delta report to the configured delta file.
+// The baseline is read from the configured baseline results file (Scorecard
+// JSON), or, if unset, computed by running Scorecard against the default branch.
+func CompareToBaseline(result *scorecard.Result, opts *options.Options) (*DeltaReport, error) {
+	base, err := baselineResult(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	docs, err := checks.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read check docs: %w", err)
+	}
+	base = headChecksOnly(base, result)
+	report, err := Compare(&base, result, docs)
+	if err != nil {
+		return nil, err
+	}
+	if report.HeadCommit == "" {
+		// Local scans of pull requests don't resolve the commit.
+		report.HeadCommit = opts.GithubSHA
+	}
+
+	contents, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshalling delta report: %w", err)
+	}
+	deltaFile := filepath.Join(opts.GithubWorkspace, opts.InputDeltaFile)
+	if err := os.WriteFile(deltaFile, contents, 0o644); err != nil {
+		return nil, fmt.Errorf("writing delta report: %w", err)
+	}
+	return report, nil
+}
+
+// headChecksOnly returns base with only the checks head has results for, not
+// counting the ones head skipped.
+// Pull requests are scored locally, with the file-based checks only, while the
+// baseline and published results have every check: without this, the other
+// checks would be reported as removed and weigh on the base aggregate score.
+func headChecksOnly(base scorecard.Result, head *scorecard.Result) scorecard.Result {
+	names := make(map[string]bool, len(head.Checks))
+	for i := range head.Checks {
+		if !IsSkipped(&head.Checks[i]) {
+			names[head.Checks[i].Name] = true
+		}
+	}
+	base.Checks = slices.DeleteFunc(slices.Clone(base.Checks), func(c checker.CheckResult) bool {
+		return !names[c.Name]
+	})
+	return base
+}
+
+func baselineResult(opts *options.Options) (scorecard.Result, error) {
+	if opts.InputBaselineResultsFile != "" {
+		return LoadResult(filepath.Join(opts.GithubWorkspace, opts.InputBaselineResultsFile))
+	}
+
+	repo, err := remoteRepo(opts, opts.GithubRepository)
+	if err != nil {
+		return scorecard.Result{}, fmt.Errorf("unable to create baseline repo: %w", err)
+	}
+	// The baseline is the head of the default branch.
+	result, err := run(repo, opts, scopts.DefaultCommit)
+	if err != nil {
+		return scorecard.Result{}, fmt.Errorf("scoring default branch: %w", err)
+	}
+	return result, nil
+}
+
+// LoadResult reads a result previously written in the json results format.
+func LoadResult(path string) (scorecard.Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return scorecard.Result{}, fmt.Errorf("opening results file: %w", err)
+	}
+	defer f.Close()
+	result, _, err := scorecard.ExperimentalFromJSON2(f)
+	if err != nil {
+		return scorecard.Result{}, fmt.Errorf("parsing results file %s: %w", path, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,183 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/checker"
+	"github.com/ossf/scorecard/v5/docs/checks"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+func TestCompareToBaseline(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	head := scorecard.Result{
+		Repo: scorecard.RepoInfo{
+			Name:      "github.com/foo/bar",
+			CommitSHA: "2222222222222222222222222222222222222222",
+		},
+		Checks: []checker.CheckResult{
+			{Name: "Binary-Artifacts", Score: 10},
+			{Name: "Code-Review", Score: 5},
+			{Name: "Fuzzing", Score: 10},
+			{Name: "Pinned-Dependencies", Score: 3},
+			{Name: "License", Score: checker.InconclusiveResultScore},
+		},
+	}
+	opts := options.Options{
+		InputBaselineResultsFile: "testdata/baseline.json",
+		InputDeltaFile:           dir + "/delta.json",
+	}
+	report, err := CompareToBaseline(&head, &opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := []CheckDelta{
+		{Name: "Binary-Artifacts", Status: DeltaUnchanged, BaseScore: 10, HeadScore: 10},
+		{Name: "Code-Review", Status: DeltaRegressed, BaseScore: 8, HeadScore: 5, Change: -3},
+		{Name: "Fuzzing", Status: DeltaImproved, BaseScore: 0, HeadScore: 10, Change: 10},
+		{Name: "Pinned-Dependencies", Status: DeltaAdded, BaseScore: -1, HeadScore: 3},
+		{Name: "License", Status: DeltaInconclusive, BaseScore: 5, HeadScore: -1},
+	}
+	if !cmp.Equal(want, report.Checks) {
+		t.Errorf("CompareToBaseline(): -want, +got:\n%s", cmp.Diff(want, report.Checks))
+	}
+	if report.BaseCommit != "1111111111111111111111111111111111111111" {
+		t.Errorf("unexpected base commit: %s", report.BaseCommit)
+	}
+	if got := report.Regressions(); len(got) != 1 || got[0].Name != "Code-Review" {
+		t.Errorf("unexpected regressions: %v", got)
+	}
+
+	contents, err := os.ReadFile(opts.InputDeltaFile)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var written DeltaReport
+	if err := json.Unmarshal(contents, &written); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !cmp.Equal(report, &written) {
+		t.Errorf("delta file: -want, +got:\n%s", cmp.Diff(report, &written))
+	}
+}
+
+func TestCompareToBaseline_headCommit(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name, commit, sha string
+		want              string
+	}{
+		{
+			name:   "scanned commit",
+			commit: "2222222222222222222222222222222222222222",
+			sha:    "3333333333333333333333333333333333333333",
+			want:   "2222222222222222222222222222222222222222",
+		},
+		{
+			name: "local scan uses the commit of the run",
+			sha:  "3333333333333333333333333333333333333333",
+			want: "3333333333333333333333333333333333333333",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			head := scorecard.Result{
+				Repo:   scorecard.RepoInfo{CommitSHA: tt.commit},
+				Checks: []checker.CheckResult{{Name: "Binary-Artifacts", Score: 10}},
+			}
+			opts := options.Options{
+				GithubSHA:                tt.sha,
+				InputBaselineResultsFile: "testdata/baseline.json",
+				InputDeltaFile:           t.TempDir() + "/delta.json",
+			}
+			report, err := CompareToBaseline(&head, &opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if report.HeadCommit != tt.want {
+				t.Errorf("HeadCommit = %q, want %q", report.HeadCommit, tt.want)
+			}
+		})
+	}
+}
+
+func TestCompareToBaseline_headChecksOnly(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	// A pull request scored locally has results for the file-based checks only.
+	head := scorecard.Result{
+		Checks: []checker.CheckResult{
+			{Name: "Binary-Artifacts", Score: 10},
+		},
+	}
+	opts := options.Options{
+		InputBaselineResultsFile: "testdata/baseline.json",
+		InputDeltaFile:           dir + "/delta.json",
+	}
+	report, err := CompareToBaseline(&head, &opts)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []CheckDelta{
+		{Name: "Binary-Artifacts", Status: DeltaUnchanged, BaseScore: 10, HeadScore: 10},
+	}
+	if !cmp.Equal(want, report.Checks) {
+		t.Errorf("CompareToBaseline(): -want, +got:\n%s", cmp.Diff(want, report.Checks))
+	}
+	if report.BaseScore != report.HeadScore {
+		t.Errorf("base score %.1f differs from head score %.1f on the same checks", report.BaseScore, report.HeadScore)
+	}
+}
+
+func TestCompare_removedCheck(t *testing.T) {
+	t.Parallel()
+	base := scorecard.Result{
+		Checks: []checker.CheckResult{
+			{Name: "Code-Review", Score: 8},
+			{Name: "Fuzzing", Score: 10},
+		},
+	}
+	head := scorecard.Result{
+		Checks: []checker.CheckResult{
+			{Name: "Code-Review", Score: 8},
+		},
+	}
+	docs, err := checks.Read()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	report, err := Compare(&base, &head, docs)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []CheckDelta{
+		{Name: "Code-Review", Status: DeltaUnchanged, BaseScore: 8, HeadScore: 8},
+		{Name: "Fuzzing", Status: DeltaRemoved, BaseScore: 10, HeadScore: -1},
+	}
+	if !cmp.Equal(want, report.Checks) {
+		t.Errorf("Compare(): -want, +got:\n%s", cmp.Diff(want, report.Checks))
+	}
+}
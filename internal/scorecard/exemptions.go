@@ -0,0 +1,55 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/ossf/scorecard-action/options"
+)
+
+var errInvalidJSONResults = errors.New("JSON results are not an object")
+
+// addExemptions lists the active ignore rules in the JSON results, under
+// "exemptions", after the fields Scorecard wrote. Results without exemptions
+// are left as Scorecard wrote them.
+func addExemptions(contents []byte, rules []options.IgnoreRule) ([]byte, error) {
+	if len(rules) == 0 {
+		return contents, nil
+	}
+	doc := bytes.TrimRight(contents, " \t\r\n")
+	if !json.Valid(doc) || len(doc) < 2 || doc[len(doc)-1] != '}' {
+		return nil, errInvalidJSONResults
+	}
+	exemptions, err := json.Marshal(rules)
+	if err != nil {
+		return nil, fmt.Errorf("encoding exemptions: %w", err)
+	}
+
+	// Splice the field in rather than re-encoding the results, which would
+	// reorder their fields.
+	out := bytes.TrimRight(doc[:len(doc)-1], " \t\r\n")
+	out = append(slices.Clip(out), ',')
+	if out[len(out)-2] == '{' {
+		out = out[:len(out)-1]
+	}
+	out = append(out, `"exemptions":`...)
+	out = append(out, exemptions...)
+	return append(out, '}', '\n'), nil
+}
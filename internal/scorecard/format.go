@@ -15,12 +15,14 @@
 package scorecard
 
 import (
+	"bytes"
 	"errors"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/ossf/scorecard-action/options"
 	"github.com/ossf/scorecard/v5/docs/checks"
@@ -31,6 +33,9 @@ import (
 
 const (
 	defaultScorecardPolicyFile = "/policy.yml"
+	// scorecardJSONFile holds the JSON results to sign and publish when the
+	// requested ones list exemptions.
+	scorecardJSONFile = "results.scorecard.json"
 )
 
 var (
@@ -39,51 +44,187 @@ var (
 )
 
 // Format provides a wrapper around the Scorecard library's various formatting functions,
-// converting our options into theirs.
+// converting our options into theirs. Every requested results format is written
+// from the same result.
 func Format(result *scorecard.Result, opts *options.Options) error {
 	if result == nil {
 		return errNoResult
 	}
 
-	// write results to both stdout and result file
-	resultFile, err := os.Create(filepath.Join(opts.GithubWorkspace, opts.InputResultsFile))
+	outputs, err := opts.ResultsOutputs()
 	if err != nil {
-		return fmt.Errorf("creating result file: %w", err)
+		return fmt.Errorf("parsing results outputs: %w", err)
 	}
-	defer resultFile.Close()
-	writer := io.MultiWriter(resultFile, os.Stdout)
 
 	docs, err := checks.Read()
 	if err != nil {
 		return fmt.Errorf("read check docs: %w", err)
 	}
 
-	switch strings.ToLower(opts.InputResultsFormat) {
+	for _, out := range outputs {
+		if err := formatAs(result, opts, docs, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// FormatAs writes the result in a single format to the given file, relative to
+// the workspace. The options are left untouched.
+func FormatAs(result *scorecard.Result, opts *options.Options, out options.ResultsOutput) error {
+	if result == nil {
+		return errNoResult
+	}
+
+	docs, err := checks.Read()
+	if err != nil {
+		return fmt.Errorf("read check docs: %w", err)
+	}
+	return formatAs(result, opts, docs, out)
+}
+
+// JSONResultsFile returns the path of the JSON results to sign and publish,
+// writing them to results.json if JSON was not one of the requested formats.
+// With ignore rules, the requested results may list exemptions, which are not
+// part of the Scorecard JSON format, so results.scorecard.json is written
+// without them instead.
+func JSONResultsFile(result *scorecard.Result, opts *options.Options) (string, error) {
+	if len(opts.IgnoreRules) == 0 {
+		return resultsFile(result, opts, options.ResultsOutput{Format: "json", File: "results.json"})
+	}
+	if result == nil {
+		return "", errNoResult
+	}
+	docs, err := checks.Read()
+	if err != nil {
+		return "", fmt.Errorf("read check docs: %w", err)
+	}
+	path := filepath.Join(opts.GithubWorkspace, scorecardJSONFile)
+	f, err := os.Create(path)
+	if err != nil {
+		return "", fmt.Errorf("creating result file: %w", err)
+	}
+	defer f.Close()
+	if err := asJSON(result, docs, f); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// InTotoResultsFile returns the path of the in-toto statement, writing it to
+// results.intoto.json if intoto was not one of the requested formats.
+func InTotoResultsFile(result *scorecard.Result, opts *options.Options) (string, error) {
+	return resultsFile(result, opts, options.ResultsOutput{Format: "intoto", File: "results.intoto.json"})
+}
+
+// resultsFile returns the path of the requested results in the given format,
+// writing them to the fallback output otherwise.
+func resultsFile(result *scorecard.Result, opts *options.Options, fallback options.ResultsOutput) (string, error) {
+	outputs, err := opts.ResultsOutputs()
+	if err != nil {
+		return "", fmt.Errorf("parsing results outputs: %w", err)
+	}
+	for _, out := range outputs {
+		if strings.EqualFold(out.Format, fallback.Format) {
+			return filepath.Join(opts.GithubWorkspace, out.File), nil
+		}
+	}
+
+	if err := FormatAs(result, opts, fallback); err != nil {
+		return "", fmt.Errorf("formatting %s results: %w", fallback.Format, err)
+	}
+	return filepath.Join(opts.GithubWorkspace, fallback.File), nil
+}
+
+func formatAs(result *scorecard.Result, opts *options.Options, docs checks.Doc, out options.ResultsOutput) error {
+	// write results to both stdout and result file
+	resultFile, err := os.Create(filepath.Join(opts.GithubWorkspace, out.File))
+	if err != nil {
+		return fmt.Errorf("creating result file: %w", err)
+	}
+	defer resultFile.Close()
+	writer := io.MultiWriter(resultFile, os.Stdout)
+
+	switch strings.ToLower(out.Format) {
 	// sarif is considered the default format when unset
 	case "", "sarif":
-		if opts.ScorecardOpts.PolicyFile == "" {
-			opts.ScorecardOpts.PolicyFile = defaultScorecardPolicyFile
+		var buf bytes.Buffer
+		if len(opts.ScorecardOpts.ProbesToRun) > 0 {
+			if err := asProbeSARIF(result, &buf); err != nil {
+				return err
+			}
+		} else {
+			policyFile := opts.ScorecardOpts.PolicyFile
+			if policyFile == "" {
+				policyFile = defaultScorecardPolicyFile
+			}
+			pol, err := policy.ParseFromFile(policyFile)
+			if err != nil {
+				return fmt.Errorf("parse policy file: %w", err)
+			}
+			err = result.AsSARIF(true, sclog.DefaultLevel, &buf, docs, pol, opts.ScorecardOpts)
+			if err != nil {
+				return fmt.Errorf("format as sarif: %w", err)
+			}
 		}
-		pol, err := policy.ParseFromFile(opts.ScorecardOpts.PolicyFile)
+		contents, err := finishSARIF(buf.Bytes(), result, opts)
 		if err != nil {
-			return fmt.Errorf("parse policy file: %w", err)
+			return err
 		}
-		err = result.AsSARIF(true, sclog.DefaultLevel, writer, docs, pol, opts.ScorecardOpts)
-		if err != nil {
-			return fmt.Errorf("format as sarif: %w", err)
+		if _, err := writer.Write(contents); err != nil {
+			return fmt.Errorf("writing sarif results: %w", err)
 		}
 	case "json":
-		err = result.AsJSON2(writer, docs, &scorecard.AsJSON2ResultOption{
-			Details:     true,
-			Annotations: false, // TODO
-			LogLevel:    sclog.DefaultLevel,
-		})
+		var buf bytes.Buffer
+		if err := asJSON(result, docs, &buf); err != nil {
+			return err
+		}
+		contents, err := addExemptions(buf.Bytes(), opts.ActiveIgnoreRules(time.Now()))
 		if err != nil {
-			return fmt.Errorf("format as JSON: %w", err)
+			return err
+		}
+		if _, err := writer.Write(contents); err != nil {
+			return fmt.Errorf("writing JSON results: %w", err)
+		}
+	case "probe":
+		if err := result.AsProbe(writer, nil); err != nil {
+			return fmt.Errorf("format as probe: %w", err)
+		}
+	case "intoto":
+		if err := asInToto(result, opts.GithubSHA, writer, docs); err != nil {
+			return err
+		}
+	case "codequality":
+		if err := asCodeQuality(result, writer, docs); err != nil {
+			return err
+		}
+	case "markdown":
+		var buf bytes.Buffer
+		if err := asMarkdown(result, &buf, docs); err != nil {
+			return fmt.Errorf("format as markdown: %w", err)
+		}
+		if _, err := writer.Write(buf.Bytes()); err != nil {
+			return fmt.Errorf("writing markdown results: %w", err)
+		}
+		if err := appendStepSummary(opts.GithubStepSummary, buf.Bytes()); err != nil {
+			return err
 		}
 	default:
-		return errUnknownFormat
+		return fmt.Errorf("%w: %s", errUnknownFormat, out.Format)
 	}
 
 	return nil
 }
+
+// asJSON writes the result in the Scorecard JSON format.
+func asJSON(result *scorecard.Result, docs checks.Doc, w io.Writer) error {
+	err := result.AsJSON2(w, docs, &scorecard.AsJSON2ResultOption{
+		Details:     true,
+		Annotations: false, // TODO
+		LogLevel:    sclog.DefaultLevel,
+	})
+	if err != nil {
+		return fmt.Errorf("format as JSON: %w", err)
+	}
+	return nil
+}
@@ -17,9 +17,11 @@ package scorecard
 import (
 	"bytes"
 	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/checker"
 	scopts "github.com/ossf/scorecard/v5/options"
 	"github.com/ossf/scorecard/v5/pkg/scorecard"
 )
@@ -46,6 +48,21 @@ func TestFormat(t *testing.T) {
 			// This isn't quite as strong of a guarantee, but dont expect this to change
 			pattern: []byte(`"name":"github.com/foo/bar"`),
 		},
+		{
+			name:    "intoto format supported",
+			format:  "intoto",
+			pattern: []byte(`"predicateType":"https://scorecard.dev/result/v0.1"`),
+		},
+		{
+			name:    "codequality format supported",
+			format:  "codequality",
+			pattern: []byte("[]"),
+		},
+		{
+			name:    "markdown format supported",
+			format:  "markdown",
+			pattern: []byte("| Check | Score | Reason |"),
+		},
 		{
 			name:    "format is case insensitive",
 			format:  "SARIF",
@@ -62,6 +79,7 @@ func TestFormat(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			t.Parallel()
 			opts := options.Options{
+				GithubSHA:          "68bc59901773ab4c051dfcea0cc4201a1567ab32",
 				InputResultsFile:   t.TempDir() + "/results",
 				InputResultsFormat: tt.format,
 				ScorecardOpts: &scopts.Options{
@@ -82,3 +100,263 @@ func TestFormat(t *testing.T) {
 		})
 	}
 }
+
+func TestFormat_stepSummary(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	summary := dir + "/summary.md"
+	if err := os.WriteFile(summary, []byte("existing\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := scorecard.Result{
+		Repo: scorecard.RepoInfo{
+			Name: "github.com/foo/bar",
+		},
+		Checks: []checker.CheckResult{
+			{Name: "Binary-Artifacts", Score: 10, Reason: "no binaries found in the repo"},
+			{Name: "Code-Review", Score: 2, Reason: "Found 2/10 approved changesets"},
+		},
+	}
+	opts := options.Options{
+		InputResultsFile:   dir + "/results.md",
+		InputResultsFormat: "markdown",
+		GithubStepSummary:  summary,
+		ScorecardOpts:      &scopts.Options{},
+	}
+	if err := Format(&result, &opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	contents, err := os.ReadFile(summary)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range [][]byte{
+		[]byte("existing\n"),
+		[]byte("| Check | Score | Reason |"),
+		[]byte("Found 2/10 approved changesets"),
+		[]byte("#### Code-Review (2)"),
+	} {
+		if !bytes.Contains(contents, want) {
+			t.Errorf("step summary missing %q:\n%s", want, contents)
+		}
+	}
+	if bytes.Contains(contents, []byte("#### Binary-Artifacts")) {
+		t.Errorf("step summary unexpectedly lists passing check:\n%s", contents)
+	}
+}
+
+func TestFormat_multipleOutputs(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	result := scorecard.Result{
+		Repo: scorecard.RepoInfo{
+			Name: "github.com/foo/bar",
+		},
+	}
+	opts := options.Options{
+		GithubWorkspace:    dir,
+		InputResultsFile:   "results.sarif, results.json,results.md",
+		InputResultsFormat: "sarif, json,markdown",
+		ScorecardOpts: &scopts.Options{
+			PolicyFile: "../../policies/template.yml",
+		},
+	}
+	want := opts
+	if err := Format(&result, &opts); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if opts.InputResultsFile != want.InputResultsFile || opts.InputResultsFormat != want.InputResultsFormat {
+		t.Errorf("Format modified the results options: %+v", opts)
+	}
+	patterns := map[string][]byte{
+		"results.sarif": []byte("sarif-schema"),
+		"results.json":  []byte(`"name":"github.com/foo/bar"`),
+		"results.md":    []byte("| Check | Score | Reason |"),
+	}
+	for file, pattern := range patterns {
+		contents, err := os.ReadFile(dir + "/" + file)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !bytes.Contains(contents, pattern) {
+			t.Errorf("%s didn't match expected pattern (%s)", file, pattern)
+		}
+	}
+}
+
+func TestFormat_mismatchedOutputs(t *testing.T) {
+	t.Parallel()
+	result := scorecard.Result{}
+	opts := options.Options{
+		InputResultsFile:   t.TempDir() + "/results.sarif",
+		InputResultsFormat: "sarif,json",
+		ScorecardOpts:      &scopts.Options{},
+	}
+	if err := Format(&result, &opts); err == nil {
+		t.Errorf("expected error for mismatched formats and files")
+	}
+}
+
+func TestInTotoResultsFile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name, format, file string
+		want               string
+	}{
+		{
+			name:   "requested format is reused",
+			format: "sarif,intoto",
+			file:   "results.sarif,statement.json",
+			want:   "statement.json",
+		},
+		{
+			name:   "written when not requested",
+			format: "sarif",
+			file:   "results.sarif",
+			want:   "results.intoto.json",
+		},
+	}
+	result := scorecard.Result{
+		Repo: scorecard.RepoInfo{
+			Name:      "github.com/foo/bar",
+			CommitSHA: "68bc59901773ab4c051dfcea0cc4201a1567ab32",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			opts := options.Options{
+				GithubWorkspace:    dir,
+				InputResultsFile:   tt.file,
+				InputResultsFormat: tt.format,
+				ScorecardOpts: &scopts.Options{
+					PolicyFile: "../../policies/template.yml",
+				},
+			}
+			if err := Format(&result, &opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := InTotoResultsFile(&result, &opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want := filepath.Join(dir, tt.want); got != want {
+				t.Errorf("InTotoResultsFile() = %q, want %q", got, want)
+			}
+			contents, err := os.ReadFile(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Contains(contents, []byte(`"gitCommit":"68bc59901773ab4c051dfcea0cc4201a1567ab32"`)) {
+				t.Errorf("statement subject is not the commit: %s", contents)
+			}
+		})
+	}
+}
+
+func TestJSONResultsFile(t *testing.T) {
+	t.Parallel()
+	rules := []options.IgnoreRule{{Check: "Signed-Releases", Reason: "internal tool", Expires: "2999-12-31"}}
+	tests := []struct {
+		name  string
+		want  string
+		rules []options.IgnoreRule
+	}{
+		{
+			name: "requested format is reused",
+			want: "results.json",
+		},
+		{
+			name:  "written without exemptions",
+			rules: rules,
+			want:  "results.scorecard.json",
+		},
+	}
+	result := scorecard.Result{
+		Repo: scorecard.RepoInfo{
+			Name:      "github.com/foo/bar",
+			CommitSHA: "68bc59901773ab4c051dfcea0cc4201a1567ab32",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			dir := t.TempDir()
+			opts := options.Options{
+				GithubWorkspace:    dir,
+				InputResultsFile:   "results.json",
+				InputResultsFormat: "json",
+				IgnoreRules:        tt.rules,
+				ScorecardOpts:      &scopts.Options{},
+			}
+			if err := Format(&result, &opts); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			got, err := JSONResultsFile(&result, &opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if want := filepath.Join(dir, tt.want); got != want {
+				t.Errorf("JSONResultsFile() = %q, want %q", got, want)
+			}
+			contents, err := os.ReadFile(got)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if bytes.Contains(contents, []byte(`"exemptions"`)) {
+				t.Errorf("JSON results to publish list exemptions: %s", contents)
+			}
+		})
+	}
+}
+
+func Test_asInTotoSubject(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name, commit, sha string
+		want              string
+		wantErr           bool
+	}{
+		{
+			name:   "scanned commit",
+			commit: "68bc59901773ab4c051dfcea0cc4201a1567ab32",
+			sha:    "1111111111111111111111111111111111111111",
+			want:   "68bc59901773ab4c051dfcea0cc4201a1567ab32",
+		},
+		{
+			name: "commit of the run",
+			sha:  "1111111111111111111111111111111111111111",
+			want: "1111111111111111111111111111111111111111",
+		},
+		{
+			name:    "no commit",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := scorecard.Result{
+				Repo: scorecard.RepoInfo{Name: "github.com/foo/bar", CommitSHA: tt.commit},
+			}
+			var buf bytes.Buffer
+			err := asInToto(&result, tt.sha, &buf, nil)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("asInToto() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !bytes.Contains(buf.Bytes(), []byte(`"gitCommit":"`+tt.want+`"`)) {
+				t.Errorf("statement subject is not %s: %s", tt.want, buf.Bytes())
+			}
+			if result.Repo.CommitSHA != tt.commit {
+				t.Errorf("asInToto() modified the result commit: %s", result.Repo.CommitSHA)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/ossf/scorecard-action/github"
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/checker"
+	"github.com/ossf/scorecard/v5/docs/checks"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+// HistoryEntry is a line of the JSON Lines score history. Inconclusive and
+// skipped checks are left out.
+type HistoryEntry struct {
+	Date   time.Time      `json:"date"`
+	Checks map[string]int `json:"checks"`
+	Commit string         `json:"commit"`
+	Score  float64        `json:"score"`
+}
+
+// RecordHistory appends the result to the score history: the history file in
+// the workspace, or in the history branch of the repository, which is created
+// if needed.
+func RecordHistory(gh *github.Client, result *scorecard.Result, opts *options.Options) error {
+	if result == nil {
+		return errNoResult
+	}
+	docs, err := checks.Read()
+	if err != nil {
+		return fmt.Errorf("read check docs: %w", err)
+	}
+	entry, err := newHistoryEntry(result, docs)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("encoding history entry: %w", err)
+	}
+	line = append(line, '\n')
+
+	if opts.InputHistoryBranch == "" {
+		return appendHistoryFile(filepath.Join(opts.GithubWorkspace, opts.HistoryFile()), line)
+	}
+	return appendHistoryBranch(gh, opts, entry.Commit, line)
+}
+
+func newHistoryEntry(result *scorecard.Result, docs checks.Doc) (HistoryEntry, error) {
+	score, err := result.GetAggregateScore(docs)
+	if err != nil {
+		return HistoryEntry{}, fmt.Errorf("computing aggregate score: %w", err)
+	}
+	entry := HistoryEntry{
+		Commit: result.Repo.CommitSHA,
+		Date:   result.Date.UTC(),
+		Score:  score,
+		Checks: map[string]int{},
+	}
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		if check.Score == checker.InconclusiveResultScore {
+			continue
+		}
+		entry.Checks[check.Name] = check.Score
+	}
+	return entry, nil
+}
+
+func appendHistoryFile(path string, line []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("creating history directory: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening history file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(line); err != nil {
+		return fmt.Errorf("appending to history file: %w", err)
+	}
+	return nil
+}
+
+func appendHistoryBranch(gh *github.Client, opts *options.Options, commit string, line []byte) error {
+	api, repo, branch, path := opts.GithubAPIURL, opts.GithubRepository, opts.InputHistoryBranch, opts.HistoryFile()
+	message := "Record Scorecard results of " + commit
+
+	exists, err := gh.BranchExists(api, repo, branch)
+	if err != nil {
+		return fmt.Errorf("looking up history branch: %w", err)
+	}
+	if !exists {
+		if err := gh.CreateOrphanBranch(api, repo, branch, path, message, line); err != nil {
+			return fmt.Errorf("creating history branch: %w", err)
+		}
+		return nil
+	}
+
+	file, err := gh.GetFile(api, repo, branch, path)
+	if err != nil && !errors.Is(err, github.ErrNotFound) {
+		return fmt.Errorf("reading history file: %w", err)
+	}
+	content := file.Content
+	if len(content) > 0 && !bytes.HasSuffix(content, []byte("\n")) {
+		content = append(content, '\n')
+	}
+	content = append(content, line...)
+	if err := gh.PutFile(api, repo, branch, path, message, content, file.SHA); err != nil {
+		return fmt.Errorf("writing history file: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,91 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/checker"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+func TestRecordHistory_file(t *testing.T) {
+	t.Parallel()
+	date := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	results := []scorecard.Result{
+		{
+			Repo: scorecard.RepoInfo{Name: "github.com/foo/bar", CommitSHA: "68bc59901773ab4c051dfcea0cc4201a1567ab32"},
+			Date: date,
+			Checks: []checker.CheckResult{
+				{Name: "Code-Review", Score: 6},
+				{Name: "Fuzzing", Score: checker.InconclusiveResultScore},
+			},
+		},
+		{
+			Repo:   scorecard.RepoInfo{Name: "github.com/foo/bar", CommitSHA: "4f7e1e2bd1f3ba5fd6b6fb6e7a1f9f8e5c4d3b2a"},
+			Date:   date.AddDate(0, 0, 1),
+			Checks: []checker.CheckResult{{Name: "Code-Review", Score: 8}},
+		},
+	}
+	opts := options.Options{
+		GithubWorkspace:  t.TempDir(),
+		InputHistoryFile: ".scorecard/history.jsonl",
+	}
+	for i := range results {
+		if err := RecordHistory(nil, &results[i], &opts); err != nil {
+			t.Fatalf("RecordHistory(): %v", err)
+		}
+	}
+
+	contents, err := os.ReadFile(filepath.Join(opts.GithubWorkspace, opts.InputHistoryFile))
+	if err != nil {
+		t.Fatalf("reading history: %v", err)
+	}
+	var got []HistoryEntry
+	scanner := bufio.NewScanner(bytes.NewReader(contents))
+	for scanner.Scan() {
+		var entry HistoryEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			t.Fatalf("parsing history line %q: %v", scanner.Text(), err)
+		}
+		got = append(got, entry)
+	}
+	want := []HistoryEntry{
+		{
+			Commit: "68bc59901773ab4c051dfcea0cc4201a1567ab32",
+			Date:   date,
+			Score:  6,
+			Checks: map[string]int{"Code-Review": 6},
+		},
+		{
+			Commit: "4f7e1e2bd1f3ba5fd6b6fb6e7a1f9f8e5c4d3b2a",
+			Date:   date.AddDate(0, 0, 1),
+			Score:  8,
+			Checks: map[string]int{"Code-Review": 8},
+		},
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("history: -want, +got:\n%s", diff)
+	}
+}
@@ -0,0 +1,83 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ossf/scorecard/v5/docs/checks"
+	sclog "github.com/ossf/scorecard/v5/log"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+var errNoSubject = errors.New("no commit to use as the in-toto subject")
+
+// inTotoStatement is an in-toto v1 statement.
+type inTotoStatement struct {
+	Type          string            `json:"_type"`
+	Subject       []json.RawMessage `json:"subject"`
+	PredicateType string            `json:"predicateType"`
+	Predicate     json.RawMessage   `json:"predicate"`
+}
+
+// asInToto writes the result as an in-toto statement with the Scorecard
+// predicate. The subject is the scanned commit, or sha for scans that don't
+// resolve it. Statements without a subject digest are refused.
+//
+// Scorecard encodes the in-toto protobuf types with encoding/json, which names
+// the statement type and predicate type fields "type" and "predicate_type".
+// Those are renamed here so in-toto consumers can parse the statement.
+func asInToto(result *scorecard.Result, sha string, writer io.Writer, docs checks.Doc) error {
+	if result.Repo.CommitSHA == "" {
+		// Local scans of pull requests don't resolve the commit.
+		r := *result
+		r.Repo.CommitSHA = sha
+		result = &r
+	}
+	if result.Repo.CommitSHA == "" {
+		return errNoSubject
+	}
+
+	var buf bytes.Buffer
+	err := result.AsInToto(&buf, docs, &scorecard.AsInTotoResultOption{
+		AsJSON2ResultOption: scorecard.AsJSON2ResultOption{
+			Details:  true,
+			LogLevel: sclog.DefaultLevel,
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("format as in-toto: %w", err)
+	}
+
+	var raw struct {
+		Type          string            `json:"type"`
+		Subject       []json.RawMessage `json:"subject"`
+		PredicateType string            `json:"predicate_type"`
+		Predicate     json.RawMessage   `json:"predicate"`
+	}
+	if err := json.Unmarshal(buf.Bytes(), &raw); err != nil {
+		return fmt.Errorf("parsing in-toto statement: %w", err)
+	}
+	statement := inTotoStatement(raw)
+
+	if err := json.NewEncoder(writer).Encode(&statement); err != nil {
+		return fmt.Errorf("writing in-toto statement: %w", err)
+	}
+	return nil
+}
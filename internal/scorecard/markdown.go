@@ -0,0 +1,129 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/ossf/scorecard/v5/checker"
+	"github.com/ossf/scorecard/v5/docs/checks"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+// checks scoring below this value have their reason and remediation listed.
+const lowScoreThreshold = 5
+
+// asMarkdown writes a job summary friendly rendering of the result: the aggregate
+// score, a table of every check, and details for the checks which scored poorly.
+func asMarkdown(result *scorecard.Result, writer io.Writer, docs checks.Doc) error {
+	score, err := result.GetAggregateScore(docs)
+	if err != nil {
+		return fmt.Errorf("computing aggregate score: %w", err)
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## OpenSSF Scorecard for %s\n\n", result.Repo.Name)
+	fmt.Fprintf(&sb, "**Aggregate score: %s / %d**\n\n", scoreToString(score), checker.MaxResultScore)
+	if result.Repo.CommitSHA != "" {
+		fmt.Fprintf(&sb, "Commit: `%s`\n\n", result.Repo.CommitSHA)
+	}
+
+	sb.WriteString("| Check | Score | Reason |\n")
+	sb.WriteString("| ----- | ----- | ------ |\n")
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		score := scoreToString(float64(check.Score))
+		if IsSkipped(check) {
+			score = "skipped"
+		}
+		fmt.Fprintf(&sb, "| %s | %s | %s |\n",
+			checkLink(check.Name, result.Scorecard.CommitSHA, docs),
+			score,
+			escapeTableCell(check.Reason),
+		)
+	}
+
+	var low []*checker.CheckResult
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		if check.Score != checker.InconclusiveResultScore && check.Score < lowScoreThreshold {
+			low = append(low, check)
+		}
+	}
+	if len(low) > 0 {
+		fmt.Fprintf(&sb, "\n### Checks scoring below %d\n", lowScoreThreshold)
+		for _, check := range low {
+			fmt.Fprintf(&sb, "\n#### %s (%d)\n\n", check.Name, check.Score)
+			fmt.Fprintf(&sb, "%s\n", check.Reason)
+			doc, err := docs.GetCheck(check.Name)
+			if err != nil {
+				continue
+			}
+			if remediation := doc.GetRemediation(); len(remediation) > 0 {
+				sb.WriteString("\nRemediation:\n")
+				for _, r := range remediation {
+					fmt.Fprintf(&sb, "- %s\n", strings.TrimSpace(r))
+				}
+			}
+			fmt.Fprintf(&sb, "\nSee the [%s documentation](%s).\n",
+				check.Name, doc.GetDocumentationURL(result.Scorecard.CommitSHA))
+		}
+	}
+
+	if _, err := io.WriteString(writer, sb.String()); err != nil {
+		return fmt.Errorf("writing markdown: %w", err)
+	}
+	return nil
+}
+
+// appendStepSummary appends the markdown rendering of the result to the
+// GitHub Actions job summary file, if one is configured.
+func appendStepSummary(path string, contents []byte) error {
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening step summary file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.Write(contents); err != nil {
+		return fmt.Errorf("writing step summary: %w", err)
+	}
+	return nil
+}
+
+func checkLink(name, commitish string, docs checks.Doc) string {
+	doc, err := docs.GetCheck(name)
+	if err != nil {
+		return name
+	}
+	return fmt.Sprintf("[%s](%s)", name, doc.GetDocumentationURL(commitish))
+}
+
+func scoreToString(score float64) string {
+	if score == checker.InconclusiveResultScore {
+		return "?"
+	}
+	return fmt.Sprintf("%.1f", score)
+}
+
+func escapeTableCell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
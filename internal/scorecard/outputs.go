@@ -0,0 +1,52 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+)
+
+var errMultilineOutput = errors.New("step output values must be single-line")
+
+// WriteStepOutputs appends the outputs to the GitHub Actions step output file,
+// if one is configured.
+func WriteStepOutputs(path string, outputs map[string]string) error {
+	if path == "" {
+		return nil
+	}
+	var sb strings.Builder
+	for _, name := range slices.Sorted(maps.Keys(outputs)) {
+		value := outputs[name]
+		if strings.ContainsAny(value, "\r\n") {
+			return fmt.Errorf("%w: %s", errMultilineOutput, name)
+		}
+		fmt.Fprintf(&sb, "%s=%s\n", name, value)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("opening step output file: %w", err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(sb.String()); err != nil {
+		return fmt.Errorf("writing step outputs: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestWriteStepOutputs(t *testing.T) {
+	t.Parallel()
+	if err := WriteStepOutputs("", map[string]string{"publish_status": "skipped"}); err != nil {
+		t.Errorf("WriteStepOutputs() without an output file: %v", err)
+	}
+
+	path := filepath.Join(t.TempDir(), "output")
+	if err := os.WriteFile(path, []byte("previous=step\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	outputs := map[string]string{
+		"publish_status":        "published",
+		"publish_tlog_index":    "42",
+		"publish_response_code": "201",
+	}
+	if err := WriteStepOutputs(path, outputs); err != nil {
+		t.Fatalf("WriteStepOutputs(): %v", err)
+	}
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "previous=step\npublish_response_code=201\npublish_status=published\npublish_tlog_index=42\n"
+	if string(contents) != want {
+		t.Errorf("output file = %q, want %q", contents, want)
+	}
+
+	err = WriteStepOutputs(path, map[string]string{"reason": "two\nlines"})
+	if !errors.Is(err, errMultilineOutput) {
+		t.Errorf("WriteStepOutputs() of a multiline value: %v, want %v", err, errMultilineOutput)
+	}
+}
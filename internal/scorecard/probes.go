@@ -0,0 +1,178 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/ossf/scorecard/v5/finding"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+const (
+	sarifSchema      = "https://raw.githubusercontent.com/oasis-tcs/sarif-spec/main/sarif-2.1/schema/sarif-schema-2.1.0.json"
+	scorecardURI     = "https://github.com/ossf/scorecard"
+	probeDocsURL     = scorecardURI + "/tree/main/probes/"
+	noFileAssociated = "no file associated with this alert"
+)
+
+// Probe findings in SARIF. Only the fields the action sets are declared.
+type (
+	probeSARIF struct {
+		Schema  string          `json:"$schema"`
+		Version string          `json:"version"`
+		Runs    []probeSARIFRun `json:"runs"`
+	}
+	probeSARIFRun struct {
+		Tool              probeSARIFTool     `json:"tool"`
+		AutomationDetails map[string]string  `json:"automationDetails"`
+		Results           []probeSARIFResult `json:"results"`
+	}
+	probeSARIFTool struct {
+		Driver probeSARIFDriver `json:"driver"`
+	}
+	probeSARIFDriver struct {
+		Name           string           `json:"name"`
+		InformationURI string           `json:"informationUri"`
+		SemanticVer    string           `json:"semanticVersion,omitempty"`
+		Rules          []probeSARIFRule `json:"rules"`
+	}
+	probeSARIFRule struct {
+		ID                   string            `json:"id"`
+		Name                 string            `json:"name"`
+		HelpURI              string            `json:"helpUri"`
+		ShortDescription     sarifText         `json:"shortDescription"`
+		Help                 sarifText         `json:"help"`
+		DefaultConfiguration map[string]string `json:"defaultConfiguration"`
+		Properties           map[string]any    `json:"properties"`
+	}
+	probeSARIFResult struct {
+		RuleID     string            `json:"ruleId"`
+		RuleIndex  int               `json:"ruleIndex"`
+		Level      string            `json:"level"`
+		Message    sarifText         `json:"message"`
+		Locations  []sarifLocation   `json:"locations"`
+		Properties map[string]string `json:"properties,omitempty"`
+	}
+	sarifText struct {
+		Text     string `json:"text"`
+		Markdown string `json:"markdown,omitempty"`
+	}
+	sarifLocation struct {
+		PhysicalLocation sarifPhysicalLocation `json:"physicalLocation"`
+	}
+	sarifPhysicalLocation struct {
+		ArtifactLocation sarifArtifactLocation `json:"artifactLocation"`
+		Region           sarifRegion           `json:"region"`
+	}
+	sarifArtifactLocation struct {
+		URI       string `json:"uri"`
+		URIBaseID string `json:"uriBaseId"`
+	}
+	sarifRegion struct {
+		StartLine uint  `json:"startLine"`
+		EndLine   *uint `json:"endLine,omitempty"`
+	}
+)
+
+// asProbeSARIF writes the probe findings of the result as SARIF, with a rule
+// per probe. Findings with the bad outcome of their probe, the ones Scorecard
+// sets a remediation on, are the results.
+func asProbeSARIF(result *scorecard.Result, writer io.Writer) error {
+	run := probeSARIFRun{
+		Tool: probeSARIFTool{Driver: probeSARIFDriver{
+			Name:           "Scorecard",
+			InformationURI: scorecardURI,
+			SemanticVer:    result.Scorecard.Version,
+			Rules:          []probeSARIFRule{},
+		}},
+		AutomationDetails: map[string]string{
+			"id": fmt.Sprintf("supply-chain/probes/%s", result.Repo.CommitSHA),
+		},
+		Results: []probeSARIFResult{},
+	}
+
+	ruleIndex := map[string]int{}
+	for i := range result.Findings {
+		f := &result.Findings[i]
+		index, ok := ruleIndex[f.Probe]
+		if !ok {
+			index = len(run.Tool.Driver.Rules)
+			ruleIndex[f.Probe] = index
+			run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, probeSARIFRule{
+				ID:                   f.Probe,
+				Name:                 f.Probe,
+				HelpURI:              probeDocsURL + f.Probe,
+				ShortDescription:     sarifText{Text: "Scorecard probe " + f.Probe},
+				Help:                 sarifText{Text: "See " + probeDocsURL + f.Probe},
+				DefaultConfiguration: map[string]string{"level": "warning"},
+				Properties:           map[string]any{"tags": []string{"supply-chain", "security", "scorecard-probe"}},
+			})
+		}
+		if f.Remediation == nil {
+			continue
+		}
+		rule := &run.Tool.Driver.Rules[index]
+		if rule.Help.Markdown == "" {
+			rule.Help = sarifText{Text: f.Remediation.Text, Markdown: f.Remediation.Markdown}
+		}
+
+		message := f.Message
+		if f.Remediation.Text != "" {
+			message = fmt.Sprintf("%s\nRemediation tip: %s", message, f.Remediation.Text)
+		}
+		run.Results = append(run.Results, probeSARIFResult{
+			RuleID:     f.Probe,
+			RuleIndex:  index,
+			Level:      "warning",
+			Message:    sarifText{Text: message},
+			Locations:  []sarifLocation{findingLocation(f)},
+			Properties: map[string]string{"outcome": string(f.Outcome)},
+		})
+	}
+
+	doc := probeSARIF{
+		Schema:  sarifSchema,
+		Version: "2.1.0",
+		Runs:    []probeSARIFRun{run},
+	}
+	encoder := json.NewEncoder(writer)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(&doc); err != nil {
+		return fmt.Errorf("writing probe sarif: %w", err)
+	}
+	return nil
+}
+
+// findingLocation returns the file of the finding. GitHub needs a location
+// for every result, so findings without a file get a placeholder, as in
+// Scorecard's SARIF.
+func findingLocation(f *finding.Finding) sarifLocation {
+	loc := sarifLocation{PhysicalLocation: sarifPhysicalLocation{
+		ArtifactLocation: sarifArtifactLocation{URI: noFileAssociated, URIBaseID: "%SRCROOT%"},
+		Region:           sarifRegion{StartLine: 1},
+	}}
+	if f.Location == nil || f.Location.Path == "" || f.Location.Type == finding.FileTypeURL {
+		return loc
+	}
+	loc.PhysicalLocation.ArtifactLocation.URI = f.Location.Path
+	if f.Location.LineStart != nil && *f.Location.LineStart > 0 {
+		loc.PhysicalLocation.Region.StartLine = *f.Location.LineStart
+	}
+	loc.PhysicalLocation.Region.EndLine = f.Location.LineEnd
+	return loc
+}
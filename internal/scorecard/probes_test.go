@@ -0,0 +1,142 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/finding"
+	scopts "github.com/ossf/scorecard/v5/options"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+func probeResult() scorecard.Result {
+	line := uint(12)
+	return scorecard.Result{
+		Repo: scorecard.RepoInfo{Name: "github.com/foo/bar", CommitSHA: "68bc59901773ab4c051dfcea0cc4201a1567ab32"},
+		Findings: []finding.Finding{
+			{
+				Probe:   "hasDangerousWorkflowScriptInjection",
+				Outcome: finding.OutcomeTrue,
+				Message: "script injection with untrusted input",
+				Location: &finding.Location{
+					Type:      finding.FileTypeText,
+					Path:      ".github/workflows/ci.yml",
+					LineStart: &line,
+				},
+				Remediation: &finding.Remediation{Text: "Avoid the dangerous workflow patterns."},
+			},
+			{
+				Probe:   "fuzzed",
+				Outcome: finding.OutcomeFalse,
+				Message: "no fuzzer integrations found",
+				Remediation: &finding.Remediation{
+					Text: "Setup one of tools we currently detect.",
+				},
+			},
+			{
+				Probe:   "fuzzed",
+				Outcome: finding.OutcomeTrue,
+				Message: "OSSFuzz integration found",
+			},
+		},
+	}
+}
+
+func Test_asProbeSARIF(t *testing.T) {
+	t.Parallel()
+	result := probeResult()
+	var buf bytes.Buffer
+	if err := asProbeSARIF(&result, &buf); err != nil {
+		t.Fatalf("asProbeSARIF(): %v", err)
+	}
+	var got probeSARIF
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("parsing sarif: %v", err)
+	}
+	if len(got.Runs) != 1 {
+		t.Fatalf("got %d runs, want 1", len(got.Runs))
+	}
+	run := got.Runs[0]
+
+	var rules []string
+	for _, r := range run.Tool.Driver.Rules {
+		rules = append(rules, r.ID)
+	}
+	if diff := cmp.Diff([]string{"hasDangerousWorkflowScriptInjection", "fuzzed"}, rules); diff != "" {
+		t.Errorf("rules: -want, +got:\n%s", diff)
+	}
+
+	type res struct {
+		RuleID    string
+		RuleIndex int
+		URI       string
+		StartLine uint
+	}
+	var results []res
+	for _, r := range run.Results {
+		loc := r.Locations[0].PhysicalLocation
+		results = append(results, res{r.RuleID, r.RuleIndex, loc.ArtifactLocation.URI, loc.Region.StartLine})
+	}
+	// The positive fuzzed finding is not an alert.
+	want := []res{
+		{"hasDangerousWorkflowScriptInjection", 0, ".github/workflows/ci.yml", 12},
+		{"fuzzed", 1, noFileAssociated, 1},
+	}
+	if diff := cmp.Diff(want, results); diff != "" {
+		t.Errorf("results: -want, +got:\n%s", diff)
+	}
+}
+
+func TestFormat_probes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		format  string
+		pattern string
+	}{
+		{format: "sarif", pattern: `"ruleId": "hasDangerousWorkflowScriptInjection"`},
+		{format: "probe", pattern: `"probe":"fuzzed"`},
+	}
+	result := probeResult()
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.format, func(t *testing.T) {
+			t.Parallel()
+			opts := options.Options{
+				InputResultsFile:   t.TempDir() + "/results",
+				InputResultsFormat: tt.format,
+				ScorecardOpts: &scopts.Options{
+					ProbesToRun: []string{"fuzzed", "hasDangerousWorkflowScriptInjection"},
+				},
+			}
+			if err := Format(&result, &opts); err != nil {
+				t.Fatalf("Format(): %v", err)
+			}
+			contents, err := os.ReadFile(opts.InputResultsFile)
+			if err != nil {
+				t.Fatalf("reading results: %v", err)
+			}
+			if !bytes.Contains(contents, []byte(tt.pattern)) {
+				t.Errorf("results don't contain %s:\n%s", tt.pattern, contents)
+			}
+		})
+	}
+}
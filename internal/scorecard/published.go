@@ -0,0 +1,65 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+
+	"github.com/ossf/scorecard/v5/docs/checks"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+var errPublishedMismatch = errors.New("published results don't match the run")
+
+// ComparePublished builds a delta report of the result against the results
+// published to the Scorecard API, in the json results format.
+func ComparePublished(result *scorecard.Result, published []byte) (*DeltaReport, error) {
+	base, err := parsePublished(published)
+	if err != nil {
+		return nil, err
+	}
+	docs, err := checks.Read()
+	if err != nil {
+		return nil, fmt.Errorf("read check docs: %w", err)
+	}
+	// Only the checks the run scored are compared, e.g. on pull requests or with
+	// enabled_checks.
+	base = headChecksOnly(base, result)
+	return Compare(&base, result, docs)
+}
+
+// VerifyPublished checks that the results published to the Scorecard API are
+// the ones of the run, to catch publications that failed.
+func VerifyPublished(result *scorecard.Result, published []byte) error {
+	base, err := parsePublished(published)
+	if err != nil {
+		return err
+	}
+	if base.Repo.CommitSHA != result.Repo.CommitSHA {
+		return fmt.Errorf("%w: published commit %s, run commit %s",
+			errPublishedMismatch, base.Repo.CommitSHA, result.Repo.CommitSHA)
+	}
+	return nil
+}
+
+func parsePublished(published []byte) (scorecard.Result, error) {
+	result, _, err := scorecard.ExperimentalFromJSON2(bytes.NewReader(published))
+	if err != nil {
+		return scorecard.Result{}, fmt.Errorf("parsing published results: %w", err)
+	}
+	return result, nil
+}
@@ -0,0 +1,112 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard/v5/checker"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+func TestComparePublished(t *testing.T) {
+	t.Parallel()
+	published, err := os.ReadFile("testdata/baseline.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result := scorecard.Result{
+		Repo: scorecard.RepoInfo{
+			Name:      "github.com/foo/bar",
+			CommitSHA: "2222222222222222222222222222222222222222",
+		},
+		Checks: []checker.CheckResult{
+			{Name: "Binary-Artifacts", Score: 10},
+			{Name: "Code-Review", Score: 9},
+			{Name: "Fuzzing", Score: 0},
+			{Name: "License", Score: 5},
+		},
+	}
+
+	report, err := ComparePublished(&result, published)
+	if err != nil {
+		t.Fatalf("ComparePublished(): %v", err)
+	}
+	want := []CheckDelta{{Name: "Code-Review", Status: DeltaImproved, BaseScore: 8, HeadScore: 9, Change: 1}}
+	if diff := cmp.Diff(want, report.Improvements()); diff != "" {
+		t.Errorf("ComparePublished(): -want, +got:\n%s", diff)
+	}
+	if len(report.Regressions()) != 0 {
+		t.Errorf("unexpected regressions: %v", report.Regressions())
+	}
+
+	// The checks the run didn't score are left out, not reported as removed.
+	partial := scorecard.Result{
+		Repo:   result.Repo,
+		Checks: []checker.CheckResult{{Name: "Code-Review", Score: 8}},
+	}
+	report, err = ComparePublished(&partial, published)
+	if err != nil {
+		t.Fatalf("ComparePublished(): %v", err)
+	}
+	want = []CheckDelta{{Name: "Code-Review", Status: DeltaUnchanged, BaseScore: 8, HeadScore: 8}}
+	if diff := cmp.Diff(want, report.Checks); diff != "" {
+		t.Errorf("ComparePublished() of partial results: -want, +got:\n%s", diff)
+	}
+	if report.BaseScore != report.HeadScore {
+		t.Errorf("ComparePublished() base score = %v, want %v", report.BaseScore, report.HeadScore)
+	}
+
+	if _, err := ComparePublished(&result, []byte("<html>")); err == nil {
+		t.Error("ComparePublished() of invalid results: got no error")
+	}
+}
+
+func TestVerifyPublished(t *testing.T) {
+	t.Parallel()
+	published, err := os.ReadFile("testdata/baseline.json")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	tests := []struct {
+		name    string
+		commit  string
+		wantErr error
+	}{
+		{
+			name:   "published",
+			commit: "1111111111111111111111111111111111111111",
+		},
+		{
+			name:    "stale publication",
+			commit:  "2222222222222222222222222222222222222222",
+			wantErr: errPublishedMismatch,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := scorecard.Result{Repo: scorecard.RepoInfo{Name: "github.com/foo/bar", CommitSHA: tt.commit}}
+			if err := VerifyPublished(&result, published); !errors.Is(err, tt.wantErr) {
+				t.Errorf("VerifyPublished() error: %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
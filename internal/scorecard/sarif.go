@@ -0,0 +1,122 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/checker"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+// sarifLog is a SARIF document decoded generically, so the action can add to
+// the SARIF written by Scorecard without mirroring its types.
+type sarifLog map[string]any
+
+// finishSARIF adds what the action knows on top of Scorecard to the SARIF
+// results: notifications for skipped checks and suppressions from the ignore
+// rules that have not expired.
+// Results with nothing to add are left as Scorecard wrote them.
+func finishSARIF(contents []byte, result *scorecard.Result, opts *options.Options) ([]byte, error) {
+	rules := opts.ActiveIgnoreRules(time.Now())
+	if len(rules) == 0 && !slices.ContainsFunc(result.Checks, func(c checker.CheckResult) bool { return IsSkipped(&c) }) {
+		return contents, nil
+	}
+
+	var doc sarifLog
+	if err := json.Unmarshal(contents, &doc); err != nil {
+		return nil, fmt.Errorf("parsing SARIF: %w", err)
+	}
+	addSkippedNotifications(doc, result)
+	addSuppressions(doc, rules)
+
+	// Keep Scorecard's indentation, and leave <, > and & in messages as is.
+	var out bytes.Buffer
+	enc := json.NewEncoder(&out)
+	enc.SetEscapeHTML(false)
+	enc.SetIndent("", "   ")
+	if err := enc.Encode(doc); err != nil {
+		return nil, fmt.Errorf("encoding SARIF: %w", err)
+	}
+	return out.Bytes(), nil
+}
+
+func (l sarifLog) runs() []map[string]any {
+	return objects(l["runs"])
+}
+
+// addSuppressions marks the results matching an ignore rule as suppressed.
+func addSuppressions(doc sarifLog, rules []options.IgnoreRule) {
+	if len(rules) == 0 {
+		return
+	}
+	for _, run := range doc.runs() {
+		var ruleNames []string
+		if tool, ok := run["tool"].(map[string]any); ok {
+			if driver, ok := tool["driver"].(map[string]any); ok {
+				for _, rule := range objects(driver["rules"]) {
+					name, _ := rule["name"].(string)
+					ruleNames = append(ruleNames, name)
+				}
+			}
+		}
+		for _, res := range objects(run["results"]) {
+			name, _ := res["ruleId"].(string)
+			if i, ok := res["ruleIndex"].(float64); ok && int(i) >= 0 && int(i) < len(ruleNames) {
+				name = ruleNames[int(i)]
+			}
+			file := resultFile(res)
+			for i := range rules {
+				if !rules[i].Matches(name, file) {
+					continue
+				}
+				res["suppressions"] = []any{map[string]any{
+					"kind":          "external",
+					"status":        "accepted",
+					"justification": rules[i].Reason,
+				}}
+				break
+			}
+		}
+	}
+}
+
+// resultFile returns the file of the first location of a SARIF result.
+func resultFile(res map[string]any) string {
+	for _, loc := range objects(res["locations"]) {
+		physical, _ := loc["physicalLocation"].(map[string]any)
+		artifact, _ := physical["artifactLocation"].(map[string]any)
+		uri, _ := artifact["uri"].(string)
+		return uri
+	}
+	return ""
+}
+
+// objects returns the JSON objects of a decoded JSON array.
+func objects(v any) []map[string]any {
+	arr, _ := v.([]any)
+	objs := make([]map[string]any, 0, len(arr))
+	for _, e := range arr {
+		if obj, ok := e.(map[string]any); ok {
+			objs = append(objs, obj)
+		}
+	}
+	return objs
+}
@@ -0,0 +1,167 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+const checkSARIF = `{
+  "runs": [{
+    "tool": {"driver": {"rules": [
+      {"id": "TokenPermissionsID", "name": "Token-Permissions"},
+      {"id": "PinnedDependenciesID", "name": "Pinned-Dependencies"}
+    ]}},
+    "results": [
+      {"ruleId": "TokenPermissionsID", "ruleIndex": 0, "locations": [{"physicalLocation": {"artifactLocation": {"uri": ".github/workflows/ci.yml"}}}]},
+      {"ruleId": "PinnedDependenciesID", "ruleIndex": 1, "locations": [{"physicalLocation": {"artifactLocation": {"uri": "docs/Dockerfile"}}}]},
+      {"ruleId": "PinnedDependenciesID", "ruleIndex": 1, "locations": [{"physicalLocation": {"artifactLocation": {"uri": "Dockerfile"}}}]}
+    ]
+  }]
+}`
+
+func Test_addSuppressions(t *testing.T) {
+	t.Parallel()
+	result := probeResult()
+	var probeBuf bytes.Buffer
+	if err := asProbeSARIF(&result, &probeBuf); err != nil {
+		t.Fatalf("asProbeSARIF(): %v", err)
+	}
+
+	tests := []struct {
+		name  string
+		sarif []byte
+		rules []options.IgnoreRule
+		want  []string
+	}{
+		{
+			name:  "no rules",
+			sarif: []byte(checkSARIF),
+			want:  []string{"", "", ""},
+		},
+		{
+			name:  "check rules",
+			sarif: []byte(checkSARIF),
+			rules: []options.IgnoreRule{
+				{Check: "token-permissions", Reason: "read-only workflows"},
+				{Check: "Pinned-Dependencies", Path: "docs", Reason: "docs images"},
+			},
+			want: []string{"read-only workflows", "docs images", ""},
+		},
+		{
+			name:  "probe rules",
+			sarif: probeBuf.Bytes(),
+			rules: []options.IgnoreRule{
+				{Probe: "hasDangerousWorkflowScriptInjection", Path: ".github/workflows/*.yml", Reason: "trusted inputs"},
+				{Probe: "fuzzed", Path: "src", Reason: "not fuzzed"},
+			},
+			want: []string{"trusted inputs", ""},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			var doc sarifLog
+			if err := json.Unmarshal(tt.sarif, &doc); err != nil {
+				t.Fatalf("parsing sarif: %v", err)
+			}
+			addSuppressions(doc, tt.rules)
+
+			var got []string
+			for _, res := range objects(doc.runs()[0]["results"]) {
+				var justification string
+				for _, s := range objects(res["suppressions"]) {
+					justification, _ = s["justification"].(string)
+				}
+				got = append(got, justification)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("suppressions: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_addExemptions(t *testing.T) {
+	t.Parallel()
+	contents := []byte(`{"date":"2026-10-18","score":7.5}` + "\n")
+
+	got, err := addExemptions(contents, nil)
+	if err != nil {
+		t.Fatalf("addExemptions(): %v", err)
+	}
+	if !bytes.Equal(got, contents) {
+		t.Errorf("addExemptions() without rules changed the results:\n%s", got)
+	}
+
+	rules := []options.IgnoreRule{{Check: "Signed-Releases", Reason: "internal tool", Expires: "2026-12-31"}}
+	got, err = addExemptions(contents, rules)
+	if err != nil {
+		t.Fatalf("addExemptions(): %v", err)
+	}
+	// The fields Scorecard wrote are kept as is, in their order.
+	if want := `{"date":"2026-10-18","score":7.5,"exemptions":[`; !bytes.HasPrefix(got, []byte(want)) {
+		t.Errorf("addExemptions() = %s, want prefix %s", got, want)
+	}
+	var doc struct {
+		Score      float64              `json:"score"`
+		Exemptions []options.IgnoreRule `json:"exemptions"`
+	}
+	if err := json.Unmarshal(got, &doc); err != nil {
+		t.Fatalf("parsing results: %v", err)
+	}
+	if doc.Score != 7.5 {
+		t.Errorf("score = %v, want 7.5", doc.Score)
+	}
+	if diff := cmp.Diff(rules, doc.Exemptions); diff != "" {
+		t.Errorf("exemptions: -want, +got:\n%s", diff)
+	}
+
+	if _, err := addExemptions([]byte("[]\n"), rules); err == nil {
+		t.Error("addExemptions() of a JSON array: got no error")
+	}
+}
+
+func Test_finishSARIF(t *testing.T) {
+	t.Parallel()
+	contents := []byte(`{"runs":[{"results":[{"message":{"text":"pin <image> & tag"}}]}]}` + "\n")
+
+	got, err := finishSARIF(contents, &scorecard.Result{}, &options.Options{})
+	if err != nil {
+		t.Fatalf("finishSARIF(): %v", err)
+	}
+	if !bytes.Equal(got, contents) {
+		t.Errorf("finishSARIF() with nothing to add changed the results:\n%s", got)
+	}
+
+	opts := &options.Options{
+		IgnoreRules: []options.IgnoreRule{{Check: "Pinned-Dependencies", Reason: "pinned by digest"}},
+	}
+	got, err = finishSARIF(contents, &scorecard.Result{}, opts)
+	if err != nil {
+		t.Fatalf("finishSARIF(): %v", err)
+	}
+	if !bytes.Contains(got, []byte("pin <image> & tag")) {
+		t.Errorf("finishSARIF() escaped the message:\n%s", got)
+	}
+}
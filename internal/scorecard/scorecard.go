@@ -22,35 +22,117 @@ import (
 	"strings"
 
 	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/checker"
 	"github.com/ossf/scorecard/v5/clients"
 	"github.com/ossf/scorecard/v5/clients/githubrepo"
+	"github.com/ossf/scorecard/v5/clients/gitlabrepo"
 	"github.com/ossf/scorecard/v5/clients/localdir"
 	sce "github.com/ossf/scorecard/v5/errors"
+	scopts "github.com/ossf/scorecard/v5/options"
 	"github.com/ossf/scorecard/v5/pkg/scorecard"
 )
 
+var errNoSupportedChecks = errors.New("none of the enabled checks are supported by the scan")
+
 // Run provides a wrapper around the Scorecard library's Run function, converting our options into theirs.
 func Run(opts *options.Options) (scorecard.Result, error) {
 	repo, err := getRepo(opts)
 	if err != nil {
 		return scorecard.Result{}, fmt.Errorf("unable to create repo: %w", err)
 	}
+	result, err := run(repo, opts, opts.ScorecardOpts.Commit)
+	if err != nil {
+		return result, err
+	}
+	tagResult(&result, opts)
+	if len(opts.ScorecardOpts.ProbesToRun) == 0 {
+		markSkipped(&result, opts.ScorecardOpts.ChecksToRun)
+	}
+	return result, nil
+}
 
+func run(repo clients.Repo, opts *options.Options, commit string) (scorecard.Result, error) {
 	var scOpts []scorecard.Option
 	if strings.EqualFold(opts.InputFileMode, "git") {
 		scOpts = append(scOpts, scorecard.WithFileModeGit())
 	}
+	if probes := opts.ScorecardOpts.ProbesToRun; len(probes) > 0 {
+		scOpts = append(scOpts, scorecard.WithProbes(probes))
+	}
+	// Scorecard fails on enabled checks the scan can't run, and silently drops
+	// the default ones, so both are left out and marked as skipped.
+	var unsupported []string
+	var reason string
+	if len(opts.ScorecardOpts.ProbesToRun) == 0 {
+		var required []checker.RequestType
+		required, reason = requiredRequestTypes(repo, commit)
+		var supported []string
+		supported, unsupported = supportedChecks(opts.ScorecardOpts.ChecksToRun, required)
+		if len(supported) == 0 {
+			return scorecard.Result{}, fmt.Errorf("%w: %s", errNoSupportedChecks, strings.Join(unsupported, ", "))
+		}
+		if len(opts.ScorecardOpts.ChecksToRun) > 0 {
+			scOpts = append(scOpts, scorecard.WithChecks(supported))
+		}
+	}
+	if commit != "" && commit != scopts.DefaultCommit {
+		scOpts = append(scOpts, scorecard.WithCommitSHA(commit))
+	}
 	result, err := scorecard.Run(context.Background(), repo, scOpts...)
 	if err != nil && !errors.Is(err, sce.ErrCheckRuntime) {
 		return scorecard.Result{}, fmt.Errorf("scorecard had an error: %w", err)
 	}
+	markUnsupported(&result, unsupported, reason)
 	return result, nil
 }
 
+// requiredRequestTypes returns the request types Scorecard requires of the
+// checks to scan the repository at the commit, and the reason the other checks
+// are skipped.
+func requiredRequestTypes(repo clients.Repo, commit string) ([]checker.RequestType, string) {
+	if _, ok := repo.(*localdir.Repo); ok {
+		return []checker.RequestType{checker.FileBased}, LocalScanReason
+	}
+	if commit != "" && commit != scopts.DefaultCommit {
+		return []checker.RequestType{checker.CommitBased}, CommitScanReason
+	}
+	return nil, ""
+}
+
 //nolint:wrapcheck // just a helper
 func getRepo(opts *options.Options) (clients.Repo, error) {
 	if opts.ScorecardOpts.Local != "" {
 		return localdir.MakeLocalDirRepo(opts.ScorecardOpts.Local)
 	}
-	return githubrepo.MakeGithubRepo(opts.ScorecardOpts.Repo)
+	return remoteRepo(opts, opts.ScorecardOpts.Repo)
+}
+
+// remoteRepo returns the repository hosted by the CI system the action runs in.
+//
+//nolint:wrapcheck // just a helper
+func remoteRepo(opts *options.Options, repo string) (clients.Repo, error) {
+	if opts.CI == options.CIGitLab {
+		return gitlabrepo.MakeGitlabRepo(repo)
+	}
+	return githubrepo.MakeGithubRepo(repo)
+}
+
+// tagResult records the ref and commit of the run in the result metadata, so
+// results of release branches and tags can be told apart. Results of the
+// default branch, the only ones published, are left as Scorecard wrote them.
+func tagResult(result *scorecard.Result, opts *options.Options) {
+	if opts.IsDefaultBranch() {
+		return
+	}
+	if opts.GithubRef != "" {
+		result.Metadata = append(result.Metadata, "ref="+opts.GithubRef)
+	}
+	sha := result.Repo.CommitSHA
+	if sha == "" {
+		// Local scans of pull requests don't resolve the commit.
+		sha = opts.GithubSHA
+	}
+	if sha != "" {
+		result.Metadata = append(result.Metadata, "commit="+sha)
+	}
 }
@@ -0,0 +1,148 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/clients/githubrepo"
+	"github.com/ossf/scorecard/v5/clients/localdir"
+	scopts "github.com/ossf/scorecard/v5/options"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+func Test_tagResult(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		commitSHA string
+		opts      options.Options
+		want      []string
+	}{
+		{
+			name:      "tag of a remote repo",
+			commitSHA: "68bc59901773ab4c051dfcea0cc4201a1567ab32",
+			opts:      options.Options{GithubRef: "refs/tags/v1.0.0"},
+			want:      []string{"ref=refs/tags/v1.0.0", "commit=68bc59901773ab4c051dfcea0cc4201a1567ab32"},
+		},
+		{
+			name: "local scan uses the commit of the run",
+			opts: options.Options{
+				GithubRef: "refs/pull/1/merge",
+				GithubSHA: "68bc59901773ab4c051dfcea0cc4201a1567ab32",
+			},
+			want: []string{"ref=refs/pull/1/merge", "commit=68bc59901773ab4c051dfcea0cc4201a1567ab32"},
+		},
+		{
+			name:      "default branch",
+			commitSHA: "68bc59901773ab4c051dfcea0cc4201a1567ab32",
+			opts:      options.Options{GithubRef: "refs/heads/main", DefaultBranch: "main"},
+		},
+		{
+			name: "nothing known",
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			result := scorecard.Result{Repo: scorecard.RepoInfo{CommitSHA: tt.commitSHA}}
+			tagResult(&result, &tt.opts)
+			if diff := cmp.Diff(tt.want, result.Metadata); diff != "" {
+				t.Errorf("tagResult(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func Test_runLocalUnsupportedChecks(t *testing.T) {
+	t.Parallel()
+	repo, err := localdir.MakeLocalDirRepo(t.TempDir())
+	if err != nil {
+		t.Fatalf("MakeLocalDirRepo(): %v", err)
+	}
+	tests := []struct {
+		name    string
+		checks  []string
+		want    map[string]string
+		wantErr bool
+	}{
+		{
+			name:   "API check is skipped",
+			checks: []string{"Code-Review", "Pinned-Dependencies"},
+			want:   map[string]string{"Code-Review": LocalScanReason},
+		},
+		{
+			name:    "only API checks",
+			checks:  []string{"Code-Review"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := options.Options{ScorecardOpts: &scopts.Options{ChecksToRun: tt.checks}}
+			result, err := run(repo, &opts, scopts.DefaultCommit)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("run() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			skipped := map[string]string{}
+			ran := false
+			for i := range result.Checks {
+				check := &result.Checks[i]
+				if IsSkipped(check) {
+					skipped[check.Name] = check.Reason
+				} else if check.Name == "Pinned-Dependencies" {
+					ran = true
+				}
+			}
+			if diff := cmp.Diff(tt.want, skipped); diff != "" {
+				t.Errorf("skipped checks: -want, +got:\n%s", diff)
+			}
+			if !ran {
+				t.Errorf("Pinned-Dependencies didn't run: %+v", result.Checks)
+			}
+		})
+	}
+}
+
+func Test_requiredRequestTypes(t *testing.T) {
+	t.Parallel()
+	repo, err := githubrepo.MakeGithubRepo("foo/bar")
+	if err != nil {
+		t.Fatalf("MakeGithubRepo(): %v", err)
+	}
+	if required, _ := requiredRequestTypes(repo, scopts.DefaultCommit); len(required) != 0 {
+		t.Errorf("requiredRequestTypes() of the head = %v, want none", required)
+	}
+	required, reason := requiredRequestTypes(repo, "68bc59901773ab4c051dfcea0cc4201a1567ab32")
+	supported, unsupported := supportedChecks([]string{"Branch-Protection", "Pinned-Dependencies"}, required)
+	if diff := cmp.Diff([]string{"Pinned-Dependencies"}, supported); diff != "" {
+		t.Errorf("supported checks of a commit: -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"Branch-Protection"}, unsupported); diff != "" {
+		t.Errorf("unsupported checks of a commit: -want, +got:\n%s", diff)
+	}
+	if reason != CommitScanReason {
+		t.Errorf("reason = %q, want %q", reason, CommitScanReason)
+	}
+}
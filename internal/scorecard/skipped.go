@@ -0,0 +1,122 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/checker"
+	"github.com/ossf/scorecard/v5/checks"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+const (
+	skippedPrefix = "Check skipped: "
+	// SkippedReason is the reason of the checks left out by enabled_checks.
+	SkippedReason = skippedPrefix + "not in enabled_checks"
+	// LocalScanReason is the reason of the checks a scan of the checked-out
+	// files, e.g. on pull requests, can't run.
+	LocalScanReason = skippedPrefix + "not supported when scanning local files"
+	// CommitScanReason is the reason of the checks that can't score a commit
+	// other than the head of the default branch, e.g. of a tag.
+	CommitScanReason = skippedPrefix + "not supported when scoring a commit other than the default branch head"
+)
+
+// markSkipped adds an inconclusive result for every default check left out of
+// the run, so each output format lists it as skipped. Nothing is added when
+// the default checks ran.
+func markSkipped(result *scorecard.Result, enabled []string) {
+	if len(enabled) == 0 {
+		return
+	}
+	for _, name := range options.DefaultChecks() {
+		if slices.Contains(enabled, name) {
+			continue
+		}
+		result.Checks = append(result.Checks, checker.CheckResult{
+			Name:   name,
+			Score:  checker.InconclusiveResultScore,
+			Reason: SkippedReason,
+		})
+	}
+}
+
+// markUnsupported adds an inconclusive result with the reason for every check
+// the scan couldn't run.
+func markUnsupported(result *scorecard.Result, unsupported []string, reason string) {
+	for _, name := range unsupported {
+		result.Checks = append(result.Checks, checker.CheckResult{
+			Name:   name,
+			Score:  checker.InconclusiveResultScore,
+			Reason: reason,
+		})
+	}
+}
+
+// supportedChecks splits the checks to run, or the default checks if none are
+// set, into those supporting the required request types and the others.
+func supportedChecks(names []string, required []checker.RequestType) (supported, unsupported []string) {
+	if len(required) == 0 {
+		return names, nil
+	}
+	if len(names) == 0 {
+		names = options.DefaultChecks()
+	}
+	all := checks.GetAllWithExperimental()
+	for _, name := range names {
+		if len(checker.ListUnsupported(required, all[name].SupportedRequestTypes)) == 0 {
+			supported = append(supported, name)
+		} else {
+			unsupported = append(unsupported, name)
+		}
+	}
+	return supported, unsupported
+}
+
+// IsSkipped reports whether the check was left out of the run, by
+// enabled_checks or because the scan couldn't run it.
+func IsSkipped(check *checker.CheckResult) bool {
+	return check.Score == checker.InconclusiveResultScore && strings.HasPrefix(check.Reason, skippedPrefix)
+}
+
+// addSkippedNotifications adds a tool execution notification to each SARIF
+// run for the skipped checks of the result. Scorecard leaves inconclusive
+// checks out of the SARIF results.
+func addSkippedNotifications(doc sarifLog, result *scorecard.Result) {
+	var notifications []any
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		if !IsSkipped(check) {
+			continue
+		}
+		notifications = append(notifications, map[string]any{
+			"level":      "note",
+			"message":    map[string]any{"text": fmt.Sprintf("%s: %s", check.Name, check.Reason)},
+			"descriptor": map[string]any{"id": check.Name},
+		})
+	}
+	if len(notifications) == 0 {
+		return
+	}
+	for _, run := range doc.runs() {
+		run["invocations"] = []any{map[string]any{
+			"executionSuccessful":        true,
+			"toolExecutionNotifications": notifications,
+		}}
+	}
+}
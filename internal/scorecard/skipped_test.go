@@ -0,0 +1,84 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"bytes"
+	"os"
+	"testing"
+
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/checker"
+	scopts "github.com/ossf/scorecard/v5/options"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+func Test_markSkipped(t *testing.T) {
+	t.Parallel()
+	result := scorecard.Result{
+		Checks: []checker.CheckResult{{Name: "Code-Review", Score: 5}},
+	}
+	markSkipped(&result, nil)
+	if len(result.Checks) != 1 {
+		t.Fatalf("markSkipped() without enabled checks added %d checks", len(result.Checks)-1)
+	}
+
+	markSkipped(&result, []string{"Code-Review"})
+	if want := len(options.DefaultChecks()); len(result.Checks) != want {
+		t.Fatalf("got %d checks, want %d", len(result.Checks), want)
+	}
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		if IsSkipped(check) == (check.Name == "Code-Review") {
+			t.Errorf("IsSkipped(%s) = %t", check.Name, IsSkipped(check))
+		}
+	}
+}
+
+func TestFormat_skippedChecks(t *testing.T) {
+	t.Parallel()
+	result := scorecard.Result{
+		Repo: scorecard.RepoInfo{
+			Name:      "github.com/foo/bar",
+			CommitSHA: "68bc59901773ab4c051dfcea0cc4201a1567ab32",
+		},
+		Checks: []checker.CheckResult{{Name: "Code-Review", Score: 5, Reason: "found 5/10 approved changesets"}},
+	}
+	markSkipped(&result, []string{"Code-Review"})
+
+	for _, format := range []string{"sarif", "json", "intoto", "markdown", "codequality"} {
+		format := format
+		t.Run(format, func(t *testing.T) {
+			t.Parallel()
+			opts := options.Options{
+				InputResultsFile:   t.TempDir() + "/results",
+				InputResultsFormat: format,
+				ScorecardOpts: &scopts.Options{
+					PolicyFile: "../../policies/template.yml",
+				},
+			}
+			if err := Format(&result, &opts); err != nil {
+				t.Fatalf("Format(): %v", err)
+			}
+			contents, err := os.ReadFile(opts.InputResultsFile)
+			if err != nil {
+				t.Fatalf("reading results: %v", err)
+			}
+			if !bytes.Contains(contents, []byte("Fuzzing")) || !bytes.Contains(contents, []byte(SkippedReason)) {
+				t.Errorf("%s results don't mark Fuzzing as skipped:\n%s", format, contents)
+			}
+		})
+	}
+}
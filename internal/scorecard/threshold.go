@@ -0,0 +1,98 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"fmt"
+
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/checker"
+	"github.com/ossf/scorecard/v5/docs/checks"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+	"github.com/ossf/scorecard/v5/policy"
+)
+
+// AggregateCheckName is used in place of a check name for violations of the minimum aggregate score.
+const AggregateCheckName = "Aggregate score"
+
+// ThresholdViolation is a score which fell below its configured minimum.
+type ThresholdViolation struct {
+	Check    string
+	Score    float64
+	MinScore float64
+}
+
+func (v ThresholdViolation) String() string {
+	return fmt.Sprintf("%s: score %s is below the minimum of %s",
+		v.Check, scoreToString(v.Score), scoreToString(v.MinScore))
+}
+
+// CheckThresholds compares the result against the minimum aggregate score and,
+// if policy enforcement is enabled, against the checks marked as `enforced` in
+// the policy file. Inconclusive scores never count as a violation.
+func CheckThresholds(result *scorecard.Result, opts *options.Options) ([]ThresholdViolation, error) {
+	if result == nil {
+		return nil, errNoResult
+	}
+
+	var violations []ThresholdViolation
+	if opts.InputMinScore > 0 {
+		docs, err := checks.Read()
+		if err != nil {
+			return nil, fmt.Errorf("read check docs: %w", err)
+		}
+		score, err := result.GetAggregateScore(docs)
+		if err != nil {
+			return nil, fmt.Errorf("computing aggregate score: %w", err)
+		}
+		if score != checker.InconclusiveResultScore && score < opts.InputMinScore {
+			violations = append(violations, ThresholdViolation{
+				Check:    AggregateCheckName,
+				Score:    score,
+				MinScore: opts.InputMinScore,
+			})
+		}
+	}
+
+	if !opts.InputFailOnPolicy {
+		return violations, nil
+	}
+
+	policyFile := opts.ScorecardOpts.PolicyFile
+	if policyFile == "" {
+		policyFile = defaultScorecardPolicyFile
+	}
+	pol, err := policy.ParseFromFile(policyFile)
+	if err != nil {
+		return nil, fmt.Errorf("parse policy file: %w", err)
+	}
+	policies := pol.GetPolicies()
+	for i := range result.Checks {
+		check := &result.Checks[i]
+		cp, ok := policies[check.Name]
+		if !ok || cp.GetMode() != policy.CheckPolicy_ENFORCED {
+			continue
+		}
+		if check.Score == checker.InconclusiveResultScore || check.Score >= int(cp.GetScore()) {
+			continue
+		}
+		violations = append(violations, ThresholdViolation{
+			Check:    check.Name,
+			Score:    float64(check.Score),
+			MinScore: float64(cp.GetScore()),
+		})
+	}
+	return violations, nil
+}
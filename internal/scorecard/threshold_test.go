@@ -0,0 +1,94 @@
+// Copyright 2024 OpenSSF Scorecard Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package scorecard
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard/v5/checker"
+	scopts "github.com/ossf/scorecard/v5/options"
+	"github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+func TestCheckThresholds(t *testing.T) {
+	t.Parallel()
+	result := scorecard.Result{
+		Repo: scorecard.RepoInfo{
+			Name: "github.com/foo/bar",
+		},
+		Checks: []checker.CheckResult{
+			{Name: "Binary-Artifacts", Score: 10},
+			{Name: "Code-Review", Score: 3},
+			{Name: "Signed-Releases", Score: 0},
+			{Name: "Fuzzing", Score: 0},
+			{Name: "Packaging", Score: checker.InconclusiveResultScore},
+		},
+	}
+	tests := []struct {
+		name         string
+		minScore     float64
+		failOnPolicy bool
+		want         []string
+	}{
+		{
+			name: "disabled by default",
+		},
+		{
+			name:         "enforced checks only",
+			failOnPolicy: true,
+			want:         []string{"Code-Review"},
+		},
+		{
+			name:     "aggregate score met",
+			minScore: 1,
+		},
+		{
+			name:         "aggregate score missed",
+			minScore:     9,
+			failOnPolicy: true,
+			want:         []string{AggregateCheckName, "Code-Review"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			opts := options.Options{
+				InputMinScore:     tt.minScore,
+				InputFailOnPolicy: tt.failOnPolicy,
+				ScorecardOpts: &scopts.Options{
+					PolicyFile: "testdata/policy.yml",
+				},
+			}
+			violations, err := CheckThresholds(&result, &opts)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			var got []string
+			for _, v := range violations {
+				if v.Score >= v.MinScore {
+					t.Errorf("violation %q is not below its minimum", v)
+				}
+				got = append(got, v.Check)
+			}
+			if !cmp.Equal(tt.want, got) {
+				t.Errorf("CheckThresholds(): -want, +got:\n%s", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
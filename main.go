@@ -16,77 +16,291 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"os"
-	"path/filepath"
+	"strconv"
+	"time"
 
+	"github.com/spf13/cobra"
+
+	"github.com/ossf/scorecard-action/github"
 	"github.com/ossf/scorecard-action/internal/scorecard"
 	"github.com/ossf/scorecard-action/options"
 	"github.com/ossf/scorecard-action/signing"
+	sc "github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+var (
+	errPullRequestTarget = errors.New("pull_request_target trigger is not supported for security reasons, " +
+		"see https://securitylab.github.com/research/github-actions-preventing-pwn-requests/")
+	errPublishStrict = errors.New("publishing failed with publish_strict")
+	errThresholds    = errors.New("scorecard thresholds missed")
 )
 
 func main() {
+	if err := newRootCmd().Execute(); err != nil {
+		log.Fatal(err)
+	}
+}
+
+func newRootCmd() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "scorecard-action",
+		Short: "Run OpenSSF Scorecard as a GitHub Action",
+		Long: `Without a subcommand, scorecard-action runs Scorecard using the
+GitHub Actions environment (GITHUB_* and INPUT_* environment variables).`,
+		Args:          cobra.NoArgs,
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAction()
+		},
+	}
+	cmd.AddCommand(newRunCmd(), newVerifyCmd())
+	return cmd
+}
+
+// runAction is the GitHub Action entrypoint. Its errors, including missed
+// thresholds, are returned so that the caller can clean up before exiting.
+func runAction() error {
 	triggerEventName := os.Getenv("GITHUB_EVENT_NAME")
 	if triggerEventName == "pull_request_target" {
-		log.Fatalf("pull_request_target trigger is not supported for security reasons" +
-			"see https://securitylab.github.com/research/github-actions-preventing-pwn-requests/")
+		return errPullRequestTarget
 	}
 
 	opts, err := getOpts()
 	if err != nil {
-		log.Fatal(err)
+		return err
 	}
 	opts.Print()
+	for _, r := range opts.ExpiredIgnoreRules(time.Now()) {
+		log.Printf("::warning::Exemption for %s%s expired on %s, its alerts are reported again: %s\n",
+			r.Check, r.Probe, r.Expires, r.Reason)
+	}
+	// Other CI systems map their pipeline source to the equivalent event.
+	triggerEventName = opts.GithubEventName
 
 	result, err := scorecard.Run(opts)
 	if err != nil {
-		log.Fatal(err)
+		return err //nolint:wrapcheck // already wrapped
 	}
 
 	if err := scorecard.Format(&result, opts); err != nil {
-		log.Fatal(err)
+		return err //nolint:wrapcheck // already wrapped
 	}
 
-	// `pull_request` does not have the necessary `token-id: write` permissions.
-	//
-	//nolint:nestif // trying to keep the refactor simpler
-	if os.Getenv(options.EnvInputPublishResults) == "true" && triggerEventName != "pull_request" {
-		// if we don't already have the results as JSON, generate them
-		if opts.InputResultsFormat != "json" {
-			opts.InputResultsFormat = "json"
-			opts.InputResultsFile = "results.json"
-			err = scorecard.Format(&result, opts)
-			if err != nil {
-				log.Fatal(err)
+	var delta *scorecard.DeltaReport
+	if opts.InputCompareBaseline && triggerEventName == "pull_request" {
+		delta, err = scorecard.CompareToBaseline(&result, opts)
+		if err != nil {
+			return fmt.Errorf("comparing against default branch: %w", err)
+		}
+		for _, c := range delta.Checks {
+			if c.Status == scorecard.DeltaRegressed || c.Status == scorecard.DeltaImproved {
+				fmt.Println(c)
 			}
 		}
+	}
 
-		resultFile := filepath.Join(opts.GithubWorkspace, opts.InputResultsFile)
-		jsonPayload, err := os.ReadFile(resultFile)
-		if err != nil {
-			log.Fatalf("reading json scorecard results: %v", err)
+	if opts.InputCommentOnPR && triggerEventName == "pull_request" && opts.CI == options.CIGitLab {
+		log.Printf("::warning::Commenting on merge requests is not supported in GitLab CI\n")
+	} else if opts.InputCommentOnPR && triggerEventName == "pull_request" {
+		// The default GitHub token needs `pull-requests: write` to comment.
+		gh := github.NewClient(context.Background())
+		gh.SetTransport(&github.TokenTransport{Token: os.Getenv(options.EnvInputInternalRepoToken)})
+		if err := scorecard.PostComment(gh, &result, delta, opts); err != nil {
+			log.Printf("::warning::Unable to comment on pull request: %v\n", err)
 		}
+	}
 
-		// Sign json results.
-		// Always use the default GitHub token, never a PAT.
-		accessToken := os.Getenv(options.EnvInputInternalRepoToken)
-		s, err := signing.New(accessToken)
+	// Pull requests, tags and other branches would mix unmerged or unrelated
+	// code into the trend.
+	if opts.HistoryEnabled() && (triggerEventName == "pull_request" || !opts.IsDefaultBranch()) {
+		log.Printf("The score history of %s is not recorded, only the default branch is.\n", opts.GithubRef)
+	} else if opts.HistoryEnabled() {
+		// Writing to the history branch needs `contents: write`.
+		gh := github.NewClient(context.Background())
+		gh.SetTransport(&github.TokenTransport{Token: os.Getenv(options.EnvInputInternalRepoToken)})
+		if err := scorecard.RecordHistory(gh, &result, opts); err != nil {
+			log.Printf("::warning::Unable to record the score history: %v\n", err)
+		}
+	}
+
+	if opts.InputComparePublished && opts.CI == options.CIGitLab {
+		log.Printf("::warning::Comparing against published results is not supported in GitLab CI\n")
+	} else if opts.InputComparePublished {
+		comparePublished(&result, opts.GithubRepository)
+	}
+
+	// `pull_request` does not have the necessary `token-id: write` permissions.
+	publish := os.Getenv(options.EnvInputPublishResults) == "true" && triggerEventName != "pull_request" &&
+		opts.CI != options.CIGitLab
+	if publish && !opts.IsDefaultBranch() {
+		// The Scorecard API keeps a single result per repository.
+		log.Printf("Results of %s are not published, only the default branch is.\n", opts.GithubRef)
+		publish = false
+	}
+
+	publishResult := signing.PublishResult{Status: signing.PublishStatusSkipped}
+	var publishErr error
+	if publish {
+		publishResult, publishErr = publishResults(&result, opts)
+		if publishErr == nil && opts.InputComparePublished {
+			verifyPublished(&result, opts.GithubRepository)
+		}
+	} else if opts.InputSigningKey != "" && triggerEventName == "pull_request" {
+		// The results of a pull request are computed from unreviewed code, so
+		// they must not carry the repository's signature.
+		log.Printf("::warning::Key-based signing is skipped on pull_request events.\n")
+	} else if opts.InputSigningKey != "" {
+		resultFile, err := scorecard.JSONResultsFile(&result, opts)
+		if err != nil {
+			return err //nolint:wrapcheck // already wrapped
+		}
+		s, err := signing.NewWithConfig("", signing.Config{
+			RekorURL:    opts.InputRekorURL,
+			KeyRef:      opts.InputSigningKey,
+			KeyPassword: opts.InputSigningKeyPassword,
+		})
 		if err != nil {
-			log.Fatalf("error SigningNew: %v", err)
+			return fmt.Errorf("error SigningNew: %w", err)
 		}
-		// TODO: does it matter if this is hardcoded as results.json or not?
 		if err = s.SignScorecardResult(resultFile); err != nil {
-			log.Fatalf("error signing scorecard json results: %v", err)
+			return fmt.Errorf("error signing scorecard json results: %w", err)
 		}
+		log.Printf("Sigstore bundle written to %s", s.BundlePath())
+	}
 
-		// Processes json results.
-		repoName := os.Getenv(options.EnvGithubRepository)
-		repoRef := os.Getenv(options.EnvGithubRef)
-		if err := s.ProcessSignature(jsonPayload, repoName, repoRef); err != nil {
-			log.Fatalf("error processing signature: %v", err)
+	if err := scorecard.WriteStepOutputs(opts.GithubOutput, publishOutputs(publishResult)); err != nil {
+		log.Printf("::warning::Unable to set the publish step outputs: %v\n", err)
+	}
+	if publishErr != nil && opts.InputPublishStrict {
+		fmt.Printf("::error ::Unable to publish the results: %v\n", publishErr)
+		return errPublishStrict
+	} else if publishErr != nil {
+		log.Printf("::warning::Unable to publish the results: %v. "+
+			"If this issue persists, check the repo issues for more information.\n", publishErr)
+	}
+
+	if opts.InputSignAttestation {
+		if err := signAttestation(&result, opts, triggerEventName); err != nil {
+			return err
 		}
 	}
+
+	violations, err := scorecard.CheckThresholds(&result, opts)
+	if err != nil {
+		return err //nolint:wrapcheck // already wrapped
+	}
+	for _, v := range violations {
+		fmt.Printf("::error ::%s\n", v)
+	}
+	var regressions []scorecard.CheckDelta
+	if delta != nil && opts.InputFailOnRegression {
+		regressions = delta.Regressions()
+	}
+	for _, r := range regressions {
+		fmt.Printf("::error ::%s\n", r)
+	}
+	if len(violations) > 0 || len(regressions) > 0 {
+		return fmt.Errorf("%w: %d threshold(s) not met, %d check(s) regressed",
+			errThresholds, len(violations), len(regressions))
+	}
+	return nil
+}
+
+// publishResults signs the JSON results and uploads them to the Scorecard API.
+// Any failure, from serializing the results to the upload, is a failed
+// publication, so that the step outputs are set and publish_strict applies.
+func publishResults(result *sc.Result, opts *options.Options) (signing.PublishResult, error) {
+	failed := signing.PublishResult{Status: signing.PublishStatusFailed}
+	resultFile, err := scorecard.JSONResultsFile(result, opts)
+	if err != nil {
+		return failed, err //nolint:wrapcheck // already wrapped
+	}
+	jsonPayload, err := os.ReadFile(resultFile)
+	if err != nil {
+		return failed, fmt.Errorf("reading json scorecard results: %w", err)
+	}
+
+	// Sign json results.
+	// Always use the default GitHub token, never a PAT.
+	accessToken := os.Getenv(options.EnvInputInternalRepoToken)
+	// The Scorecard API only accepts public-good Sigstore signatures.
+	s, err := signing.New(accessToken)
+	if err != nil {
+		return failed, fmt.Errorf("error SigningNew: %w", err)
+	}
+	// TODO: does it matter if this is hardcoded as results.json or not?
+	if err = s.SignScorecardResult(resultFile); err != nil {
+		return failed, fmt.Errorf("error signing scorecard json results: %w", err)
+	}
+	log.Printf("Sigstore bundle written to %s", s.BundlePath())
+
+	// Processes json results.
+	repoName := os.Getenv(options.EnvGithubRepository)
+	repoRef := os.Getenv(options.EnvGithubRef)
+	return s.Publish(jsonPayload, repoName, repoRef) //nolint:wrapcheck // already wrapped
+}
+
+// publishOutputs returns the step outputs describing the publication. The
+// transparency log index and response code are empty when unknown.
+func publishOutputs(r signing.PublishResult) map[string]string {
+	outputs := map[string]string{
+		"publish_status":        r.Status,
+		"publish_tlog_index":    "",
+		"publish_response_code": "",
+	}
+	// Results that failed before being signed have no log entry.
+	if r.TlogIndex > 0 {
+		outputs["publish_tlog_index"] = strconv.FormatInt(r.TlogIndex, 10)
+	}
+	if r.ResponseCode != 0 {
+		outputs["publish_response_code"] = strconv.Itoa(r.ResponseCode)
+	}
+	return outputs
+}
+
+// comparePublished flags the checks whose score changed since the results last
+// published to the Scorecard API.
+func comparePublished(result *sc.Result, repoName string) {
+	published, err := signing.FetchPublishedResult(repoName)
+	if errors.Is(err, signing.ErrNoPublishedResult) {
+		log.Printf("No published results of %s to compare against.\n", repoName)
+		return
+	}
+	if err != nil {
+		log.Printf("::warning::Unable to fetch the published results: %v\n", err)
+		return
+	}
+	delta, err := scorecard.ComparePublished(result, published)
+	if err != nil {
+		log.Printf("::warning::Unable to compare against the published results: %v\n", err)
+		return
+	}
+	for _, c := range delta.Checks {
+		switch c.Status {
+		case scorecard.DeltaRegressed:
+			fmt.Printf("::warning ::%s since the published results of %s\n", c, delta.BaseCommit)
+		case scorecard.DeltaImproved:
+			fmt.Printf("::notice ::%s since the published results of %s\n", c, delta.BaseCommit)
+		}
+	}
+}
+
+// verifyPublished checks that the Scorecard API serves the results just
+// published, since publication failures are only logged.
+func verifyPublished(result *sc.Result, repoName string) {
+	published, err := signing.FetchPublishedResult(repoName)
+	if err == nil {
+		err = scorecard.VerifyPublished(result, published)
+	}
+	if err != nil {
+		log.Printf("::warning::The results may not have been published: %v\n", err)
+	}
 }
 
 func getOpts() (*options.Options, error) {
@@ -99,3 +313,36 @@ func getOpts() (*options.Options, error) {
 	}
 	return opts, nil
 }
+
+// signAttestation signs the results as an in-toto attestation, with the
+// signing key if set, else keylessly.
+func signAttestation(result *sc.Result, opts *options.Options, triggerEventName string) error {
+	// Keyless signing needs the `id-token: write` permission `pull_request` lacks,
+	// and the key must not vouch for the results of unreviewed code.
+	if triggerEventName == "pull_request" {
+		log.Printf("::warning::Attestations are not signed on pull_request events, skipping the attestation.\n")
+		return nil
+	}
+	statementFile, err := scorecard.InTotoResultsFile(result, opts)
+	if err != nil {
+		return err //nolint:wrapcheck // already wrapped
+	}
+	s, err := signing.NewWithConfig(os.Getenv(options.EnvInputInternalRepoToken), signing.Config{
+		FulcioURL:   opts.InputFulcioURL,
+		RekorURL:    opts.InputRekorURL,
+		OIDCIssuer:  opts.InputOIDCIssuer,
+		TUFMirror:   opts.InputTUFMirror,
+		TUFRoot:     opts.InputTUFRoot,
+		KeyRef:      opts.InputSigningKey,
+		KeyPassword: opts.InputSigningKeyPassword,
+	})
+	if err != nil {
+		return fmt.Errorf("error SigningNew: %w", err)
+	}
+	bundlePath, err := s.SignAttestation(statementFile)
+	if err != nil {
+		return fmt.Errorf("error signing scorecard attestation: %w", err)
+	}
+	log.Printf("Attestation bundle written to %s", bundlePath)
+	return nil
+}
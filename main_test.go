@@ -0,0 +1,77 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+
+	"github.com/ossf/scorecard-action/options"
+	"github.com/ossf/scorecard-action/signing"
+	sc "github.com/ossf/scorecard/v5/pkg/scorecard"
+)
+
+func TestPublishOutputs(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name   string
+		want   map[string]string
+		result signing.PublishResult
+	}{
+		{
+			name:   "skipped",
+			result: signing.PublishResult{Status: signing.PublishStatusSkipped},
+			want:   map[string]string{"publish_status": "skipped", "publish_tlog_index": "", "publish_response_code": ""},
+		},
+		{
+			name:   "failed before signing",
+			result: signing.PublishResult{Status: signing.PublishStatusFailed},
+			want:   map[string]string{"publish_status": "failed", "publish_tlog_index": "", "publish_response_code": ""},
+		},
+		{
+			name:   "failed upload",
+			result: signing.PublishResult{Status: signing.PublishStatusFailed, TlogIndex: 42, ResponseCode: 500},
+			want:   map[string]string{"publish_status": "failed", "publish_tlog_index": "42", "publish_response_code": "500"},
+		},
+		{
+			name:   "published",
+			result: signing.PublishResult{Status: signing.PublishStatusPublished, TlogIndex: 42, ResponseCode: 201},
+			want:   map[string]string{"publish_status": "published", "publish_tlog_index": "42", "publish_response_code": "201"},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			if diff := cmp.Diff(tt.want, publishOutputs(tt.result)); diff != "" {
+				t.Errorf("publishOutputs(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestPublishResults_serializationFailure(t *testing.T) {
+	t.Parallel()
+	// One results file for two formats can't be resolved.
+	opts := options.Options{InputResultsFormat: "json,sarif", InputResultsFile: "results.json"}
+	got, err := publishResults(&sc.Result{}, &opts)
+	if err == nil {
+		t.Fatal("publishResults() succeeded without JSON results")
+	}
+	if got.Status != signing.PublishStatusFailed {
+		t.Errorf("publishResults() status = %s, want %s", got.Status, signing.PublishStatusFailed)
+	}
+}
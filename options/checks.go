@@ -0,0 +1,107 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/ossf/scorecard/v5/checks"
+)
+
+var (
+	errUnknownCheck       = errors.New("unknown check")
+	errInvalidCheckToggle = errors.New("invalid check toggle")
+	errNoChecksEnabled    = errors.New("no checks enabled")
+	errChecksWithPublish  = errors.New("enabled checks cannot be restricted with publish_results")
+)
+
+// Checks returns the checks to run, with the names registered in Scorecard.
+// The enabled_checks input takes precedence over ENABLED_CHECKS, and
+// ENABLE_LICENSE and ENABLE_DANGEROUS_WORKFLOW add or remove their check.
+// A nil list runs the default checks.
+func (o *Options) Checks() ([]string, error) {
+	names := map[string]string{}
+	for name := range checks.GetAllWithExperimental() {
+		names[strings.ToLower(name)] = name
+	}
+
+	list := nonEmpty(splitList(o.InputEnabledChecks))
+	if len(list) == 0 {
+		list = nonEmpty(splitList(o.EnabledChecks))
+	}
+	restricted := len(list) > 0
+
+	var enabled []string
+	for _, c := range list {
+		name, ok := names[strings.ToLower(c)]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", errUnknownCheck, c)
+		}
+		if !slices.Contains(enabled, name) {
+			enabled = append(enabled, name)
+		}
+	}
+
+	toggles := []struct{ env, value, check string }{
+		{EnvEnableLicense, o.EnableLicense, checks.CheckLicense},
+		{EnvEnableDangerousWorkflow, o.EnableDangerousWorkflow, checks.CheckDangerousWorkflow},
+	}
+	for _, t := range toggles {
+		if t.value == "" {
+			continue
+		}
+		on, err := strconv.ParseBool(t.value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s=%q", errInvalidCheckToggle, t.env, t.value)
+		}
+		switch {
+		case on && restricted && !slices.Contains(enabled, t.check):
+			enabled = append(enabled, t.check)
+		case !on:
+			if !restricted {
+				enabled = DefaultChecks()
+				restricted = true
+			}
+			enabled = slices.DeleteFunc(enabled, func(name string) bool { return name == t.check })
+		}
+	}
+
+	if !restricted {
+		return nil, nil
+	}
+	if len(enabled) == 0 {
+		return nil, errNoChecksEnabled
+	}
+	return enabled, nil
+}
+
+// DefaultChecks returns the sorted names of the checks Scorecard runs by default.
+func DefaultChecks() []string {
+	all := checks.GetAll()
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+func nonEmpty(elems []string) []string {
+	return slices.DeleteFunc(elems, func(s string) bool { return s == "" })
+}
@@ -0,0 +1,143 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	scopts "github.com/ossf/scorecard/v5/options"
+)
+
+func TestChecks(t *testing.T) {
+	t.Parallel()
+	withoutLicense := slices.DeleteFunc(DefaultChecks(), func(name string) bool { return name == "License" })
+	tests := []struct {
+		name    string
+		opts    Options
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "default checks",
+		},
+		{
+			name: "input is matched case-insensitively",
+			opts: Options{InputEnabledChecks: "code-review, Binary-Artifacts,CODE-REVIEW"},
+			want: []string{"Code-Review", "Binary-Artifacts"},
+		},
+		{
+			name: "input takes precedence over ENABLED_CHECKS",
+			opts: Options{InputEnabledChecks: "Code-Review", EnabledChecks: "Fuzzing"},
+			want: []string{"Code-Review"},
+		},
+		{
+			name: "ENABLED_CHECKS",
+			opts: Options{EnabledChecks: "Fuzzing"},
+			want: []string{"Fuzzing"},
+		},
+		{
+			name:    "unknown check",
+			opts:    Options{InputEnabledChecks: "Code-Review,No-Such-Check"},
+			wantErr: errUnknownCheck,
+		},
+		{
+			name: "toggle adds a check to the list",
+			opts: Options{InputEnabledChecks: "Code-Review", EnableDangerousWorkflow: "true"},
+			want: []string{"Code-Review", "Dangerous-Workflow"},
+		},
+		{
+			name: "toggle on keeps the default checks",
+			opts: Options{EnableLicense: "true"},
+		},
+		{
+			name: "toggle off removes a default check",
+			opts: Options{EnableLicense: "false"},
+			want: withoutLicense,
+		},
+		{
+			name: "toggle off removes a listed check",
+			opts: Options{InputEnabledChecks: "License,Fuzzing", EnableLicense: "0"},
+			want: []string{"Fuzzing"},
+		},
+		{
+			name:    "invalid toggle",
+			opts:    Options{EnableLicense: "maybe"},
+			wantErr: errInvalidCheckToggle,
+		},
+		{
+			name:    "no checks left",
+			opts:    Options{InputEnabledChecks: "License", EnableLicense: "false"},
+			wantErr: errNoChecksEnabled,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			got, err := tt.opts.Checks()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Checks() error: %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Checks(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // Validate reads the environment.
+func TestValidateChecksWithPublish(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		publish string
+		wantErr bool
+	}{
+		{
+			name:    "default checks",
+			publish: trueStr,
+		},
+		{
+			name:    "enabled checks",
+			opts:    Options{InputEnabledChecks: "Code-Review"},
+			publish: trueStr,
+			wantErr: true,
+		},
+		{
+			name:    "toggle off",
+			opts:    Options{EnableLicense: "false"},
+			publish: trueStr,
+			wantErr: true,
+		},
+		{
+			name: "enabled checks without publishing",
+			opts: Options{InputEnabledChecks: "Code-Review"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvGithubAuthToken, "test-token")
+			t.Setenv(EnvInputPublishResults, tt.publish)
+			tt.opts.ScorecardOpts = scopts.New()
+			err := tt.opts.Validate()
+			if got := errors.Is(err, errChecksWithPublish); got != tt.wantErr {
+				t.Errorf("Validate() error: %v, want errChecksWithPublish: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
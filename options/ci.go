@@ -0,0 +1,143 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/caarlos0/env/v6"
+)
+
+// CI systems the action can run in.
+const (
+	CIGitHubActions = "github-actions"
+	CIGitLab        = "gitlab-ci"
+)
+
+// CIEnvironment fills Options from the environment of a CI system. Options
+// keep the GitHub Actions names for the run context, e.g. GithubRef, which
+// other CI systems map their own variables to.
+type CIEnvironment interface {
+	// Name identifies the CI system, e.g. CIGitLab.
+	Name() string
+	// Detect reports whether the process runs in this CI system.
+	Detect() bool
+	// Apply sets the run context of the Options from the environment.
+	Apply(o *Options) error
+}
+
+// ciEnvironments are tried in order, GitHub Actions being the fallback.
+var ciEnvironments = []CIEnvironment{
+	gitlabCI{},
+	githubActions{},
+}
+
+func detectCI() CIEnvironment {
+	for _, ci := range ciEnvironments {
+		if ci.Detect() {
+			return ci
+		}
+	}
+	return githubActions{}
+}
+
+type githubActions struct{}
+
+func (githubActions) Name() string {
+	return CIGitHubActions
+}
+
+func (githubActions) Detect() bool {
+	return os.Getenv("GITHUB_ACTIONS") == trueStr
+}
+
+func (githubActions) Apply(o *Options) error {
+	// GITHUB_AUTH_TOKEN
+	// Needs to be set *before* setRepoInfo() is invoked.
+	// setRepoInfo() uses the GITHUB_AUTH_TOKEN env for querying the REST API.
+	if _, tokenSet := os.LookupEnv(EnvGithubAuthToken); !tokenSet {
+		inputToken := os.Getenv(EnvInputRepoToken)
+		os.Setenv(EnvGithubAuthToken, inputToken)
+	}
+	if err := o.setRepoInfo(); err != nil {
+		return fmt.Errorf("parsing repo info: %w", err)
+	}
+	return nil
+}
+
+// gitlabEnv are the GitLab CI predefined variables the action uses.
+// https://docs.gitlab.com/ee/ci/variables/predefined_variables.html
+type gitlabEnv struct {
+	ServerHost        string `env:"CI_SERVER_HOST"`
+	APIURL            string `env:"CI_API_V4_URL"`
+	ProjectPath       string `env:"CI_PROJECT_PATH"`
+	ProjectDir        string `env:"CI_PROJECT_DIR"`
+	ProjectVisibility string `env:"CI_PROJECT_VISIBILITY"`
+	DefaultBranch     string `env:"CI_DEFAULT_BRANCH"`
+	CommitRefName     string `env:"CI_COMMIT_REF_NAME"`
+	CommitTag         string `env:"CI_COMMIT_TAG"`
+	CommitSHA         string `env:"CI_COMMIT_SHA"`
+	PipelineSource    string `env:"CI_PIPELINE_SOURCE"`
+	MergeRequestIID   int    `env:"CI_MERGE_REQUEST_IID"`
+}
+
+type gitlabCI struct{}
+
+func (gitlabCI) Name() string {
+	return CIGitLab
+}
+
+func (gitlabCI) Detect() bool {
+	return os.Getenv("GITLAB_CI") == trueStr
+}
+
+func (gitlabCI) Apply(o *Options) error {
+	var e gitlabEnv
+	if err := env.Parse(&e); err != nil {
+		return fmt.Errorf("parsing GitLab CI env vars: %w", err)
+	}
+
+	// Scorecard identifies GitLab projects by host and path.
+	o.GithubRepository = e.ServerHost + "/" + e.ProjectPath
+	o.GithubAPIURL = e.APIURL
+	o.GithubWorkspace = e.ProjectDir
+	o.GithubEventName = gitlabEventName(e.PipelineSource)
+	if e.CommitTag != "" {
+		o.GithubRef = "refs/tags/" + e.CommitTag
+	} else {
+		o.GithubRef = "refs/heads/" + e.CommitRefName
+	}
+	o.GithubSHA = e.CommitSHA
+	o.DefaultBranch = e.DefaultBranch
+	o.PullRequestNumber = e.MergeRequestIID
+	o.PrivateRepoStr = strconv.FormatBool(e.ProjectVisibility != "public")
+	o.IsForkStr = strconv.FormatBool(false)
+	return nil
+}
+
+// gitlabEventName maps a GitLab pipeline source to the equivalent GitHub
+// Actions event, so merge requests are scored like pull requests.
+func gitlabEventName(pipelineSource string) string {
+	switch pipelineSource {
+	case "merge_request_event", "external_pull_request_event":
+		return pullRequestEvent
+	case "schedule":
+		return "schedule"
+	default:
+		return pushEvent
+	}
+}
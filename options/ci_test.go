@@ -0,0 +1,174 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+//nolint:paralleltest // uses t.Setenv
+func TestDetectCI(t *testing.T) {
+	tests := []struct {
+		name     string
+		gitlabCI string
+		github   string
+		want     string
+	}{
+		{
+			name:   "GitHub Actions",
+			github: "true",
+			want:   CIGitHubActions,
+		},
+		{
+			name:     "GitLab CI",
+			gitlabCI: "true",
+			want:     CIGitLab,
+		},
+		{
+			name: "falls back to GitHub Actions",
+			want: CIGitHubActions,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("GITLAB_CI", tt.gitlabCI)
+			t.Setenv("GITHUB_ACTIONS", tt.github)
+			if got := detectCI().Name(); got != tt.want {
+				t.Errorf("detectCI() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // uses t.Setenv
+func TestGitlabCIApply(t *testing.T) {
+	type fields struct {
+		GithubRepository  string
+		GithubAPIURL      string
+		GithubWorkspace   string
+		GithubEventName   string
+		GithubRef         string
+		GithubSHA         string
+		DefaultBranch     string
+		PullRequestNumber int
+		PrivateRepoStr    string
+		IsForkStr         string
+	}
+	tests := []struct {
+		name string
+		env  map[string]string
+		want fields
+	}{
+		{
+			name: "push to branch",
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE":    "push",
+				"CI_COMMIT_REF_NAME":    "main",
+				"CI_PROJECT_VISIBILITY": "public",
+			},
+			want: fields{
+				GithubEventName: pushEvent,
+				GithubRef:       "refs/heads/main",
+				PrivateRepoStr:  "false",
+			},
+		},
+		{
+			name: "tag pipeline",
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE":    "push",
+				"CI_COMMIT_REF_NAME":    "v1.0.0",
+				"CI_COMMIT_TAG":         "v1.0.0",
+				"CI_PROJECT_VISIBILITY": "internal",
+			},
+			want: fields{
+				GithubEventName: pushEvent,
+				GithubRef:       "refs/tags/v1.0.0",
+				PrivateRepoStr:  "true",
+			},
+		},
+		{
+			name: "merge request",
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE":    "merge_request_event",
+				"CI_COMMIT_REF_NAME":    "feature",
+				"CI_MERGE_REQUEST_IID":  "42",
+				"CI_PROJECT_VISIBILITY": "private",
+			},
+			want: fields{
+				GithubEventName:   pullRequestEvent,
+				GithubRef:         "refs/heads/feature",
+				PullRequestNumber: 42,
+				PrivateRepoStr:    "true",
+			},
+		},
+		{
+			name: "scheduled pipeline",
+			env: map[string]string{
+				"CI_PIPELINE_SOURCE":    "schedule",
+				"CI_COMMIT_REF_NAME":    "main",
+				"CI_PROJECT_VISIBILITY": "public",
+			},
+			want: fields{
+				GithubEventName: "schedule",
+				GithubRef:       "refs/heads/main",
+				PrivateRepoStr:  "false",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv("CI_SERVER_HOST", "gitlab.example.com")
+			t.Setenv("CI_API_V4_URL", "https://gitlab.example.com/api/v4")
+			t.Setenv("CI_PROJECT_PATH", "group/project")
+			t.Setenv("CI_PROJECT_DIR", "/builds/group/project")
+			t.Setenv("CI_DEFAULT_BRANCH", "main")
+			t.Setenv("CI_COMMIT_SHA", "68bc59901773ab4c051dfcea0cc4201a1567ab32")
+			for _, k := range []string{"CI_COMMIT_TAG", "CI_MERGE_REQUEST_IID"} {
+				t.Setenv(k, "")
+			}
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+			tt.want.GithubRepository = "gitlab.example.com/group/project"
+			tt.want.GithubAPIURL = "https://gitlab.example.com/api/v4"
+			tt.want.GithubWorkspace = "/builds/group/project"
+			tt.want.GithubSHA = "68bc59901773ab4c051dfcea0cc4201a1567ab32"
+			tt.want.DefaultBranch = "main"
+			tt.want.IsForkStr = "false"
+
+			var o Options
+			if err := (gitlabCI{}).Apply(&o); err != nil {
+				t.Fatalf("Apply(): %v", err)
+			}
+			got := fields{
+				GithubRepository:  o.GithubRepository,
+				GithubAPIURL:      o.GithubAPIURL,
+				GithubWorkspace:   o.GithubWorkspace,
+				GithubEventName:   o.GithubEventName,
+				GithubRef:         o.GithubRef,
+				GithubSHA:         o.GithubSHA,
+				DefaultBranch:     o.DefaultBranch,
+				PullRequestNumber: o.PullRequestNumber,
+				PrivateRepoStr:    o.PrivateRepoStr,
+				IsForkStr:         o.IsForkStr,
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Apply(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,273 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"bytes"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ossf/scorecard/v5/checks"
+	"github.com/santhosh-tekuri/jsonschema/v6"
+	"sigs.k8s.io/yaml"
+)
+
+// DefaultConfigFile is the configuration file read from the workspace when
+// the config_file input is unset.
+const DefaultConfigFile = ".github/scorecard.yml"
+
+const (
+	configSchemaURL     = "https://github.com/ossf/scorecard-action/options/config.schema.json"
+	exemptionsSchemaURL = configSchemaURL + "#/$defs/exemptionsFile"
+)
+
+var errInvalidConfig = errors.New("invalid config file")
+
+//go:embed config.schema.json
+var configSchemaJSON []byte
+
+// configSchemas are the schemas of the configuration and exemptions files,
+// compiled once from config.schema.json.
+var configSchemas = sync.OnceValues(func() (map[string]*jsonschema.Schema, error) {
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(configSchemaJSON))
+	if err != nil {
+		return nil, fmt.Errorf("parsing config schema: %w", err)
+	}
+	c := jsonschema.NewCompiler()
+	if err := c.AddResource(configSchemaURL, doc); err != nil {
+		return nil, fmt.Errorf("adding config schema: %w", err)
+	}
+	schemas := map[string]*jsonschema.Schema{}
+	for _, ref := range []string{configSchemaURL, exemptionsSchemaURL} {
+		schema, err := c.Compile(ref)
+		if err != nil {
+			return nil, fmt.Errorf("compiling config schema: %w", err)
+		}
+		schemas[ref] = schema
+	}
+	return schemas, nil
+})
+
+// Config is the configuration file of the action. Inputs set in the
+// environment take precedence over its values.
+type Config struct {
+	Version    int              `json:"version"`
+	Checks     []string         `json:"checks,omitempty"`
+	Probes     []string         `json:"probes,omitempty"`
+	PolicyFile string           `json:"policy_file,omitempty"`
+	Thresholds ConfigThresholds `json:"thresholds"`
+	Results    []ResultsOutput  `json:"results,omitempty"`
+	Ignore     []IgnoreRule     `json:"ignore,omitempty"`
+}
+
+// ConfigThresholds are the threshold inputs of the configuration file.
+type ConfigThresholds struct {
+	MinScore         *float64 `json:"min_score,omitempty"`
+	FailOnPolicy     *bool    `json:"fail_on_policy,omitempty"`
+	FailOnRegression *bool    `json:"fail_on_regression,omitempty"`
+}
+
+// IgnoreRule suppresses the alerts of a check or probe, optionally only those
+// in a file or directory, and optionally until an expiry date.
+type IgnoreRule struct {
+	Check  string `json:"check,omitempty"`
+	Probe  string `json:"probe,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Reason string `json:"reason"`
+	// Expires is the last day (YYYY-MM-DD, UTC) the rule applies.
+	Expires string `json:"expires,omitempty"`
+}
+
+// LoadConfig reads a configuration file and validates it against the schema.
+func LoadConfig(path string) (*Config, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading config file: %w", err)
+	}
+	return parseConfig(contents)
+}
+
+func parseConfig(contents []byte) (*Config, error) {
+	var c Config
+	if err := decodeYAML(contents, configSchemaURL, &c); err != nil {
+		return nil, err
+	}
+	return &c, nil
+}
+
+// decodeYAML validates YAML contents against one of the config schemas, then
+// decodes them into v.
+func decodeYAML(contents []byte, schemaURL string, v any) error {
+	jsonContents, err := yaml.YAMLToJSON(contents)
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidConfig, err)
+	}
+	doc, err := jsonschema.UnmarshalJSON(bytes.NewReader(jsonContents))
+	if err != nil {
+		return fmt.Errorf("%w: %w", errInvalidConfig, err)
+	}
+	schemas, err := configSchemas()
+	if err != nil {
+		return err
+	}
+	if err := schemas[schemaURL].Validate(doc); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidConfig, err)
+	}
+	if err := json.Unmarshal(jsonContents, v); err != nil {
+		return fmt.Errorf("%w: %w", errInvalidConfig, err)
+	}
+	return nil
+}
+
+// workspaceFile returns the file set by an input, or the default file, relative
+// to the workspace, and whether it has to exist.
+func (o *Options) workspaceFile(input, defaultFile string) (string, bool) {
+	path, required := input, true
+	if path == "" {
+		path, required = defaultFile, false
+	}
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(o.GithubWorkspace, path)
+	}
+	return path, required
+}
+
+// loadConfig reads the configuration file, if any, into the options. Errors
+// are kept for Validate to report.
+func (o *Options) loadConfig() {
+	path, required := o.workspaceFile(o.InputConfigFile, DefaultConfigFile)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) && !required {
+		return
+	}
+	c, err := LoadConfig(path)
+	if err != nil {
+		o.configErr = fmt.Errorf("loading %s: %w", path, err)
+		return
+	}
+	o.ConfigFile = path
+	// The workspace holds the head of the pull request, so the file is under the
+	// control of its author.
+	if o.isPullRequestEvent() {
+		fmt.Printf("::warning::%s is read from the pull request, whose author can change it, "+
+			"e.g. to lower the thresholds.\n", path)
+	}
+	o.applyConfig(c)
+}
+
+// applyConfig sets the inputs left empty in the environment from the
+// configuration file.
+func (o *Options) applyConfig(c *Config) {
+	unset := func(key string) bool { return os.Getenv(key) == "" }
+
+	if len(c.Checks) > 0 && unset(EnvInputEnabledChecks) {
+		o.InputEnabledChecks = strings.Join(c.Checks, ",")
+	}
+	if len(c.Probes) > 0 && unset(EnvInputProbes) {
+		o.InputProbes = strings.Join(c.Probes, ",")
+	}
+	if c.PolicyFile != "" && unset(EnvInputPolicyFile) {
+		o.InputPolicyFile = c.PolicyFile
+	}
+	if c.Thresholds.MinScore != nil && unset(EnvInputMinScore) {
+		o.InputMinScore = *c.Thresholds.MinScore
+	}
+	if c.Thresholds.FailOnPolicy != nil && unset(EnvInputFailOnPolicy) {
+		o.InputFailOnPolicy = *c.Thresholds.FailOnPolicy
+	}
+	if c.Thresholds.FailOnRegression != nil && unset(EnvInputFailOnRegression) {
+		o.InputFailOnRegression = *c.Thresholds.FailOnRegression
+	}
+	if len(c.Results) > 0 && unset(EnvInputResultsFormat) && unset(EnvInputResultsFile) {
+		formats := make([]string, 0, len(c.Results))
+		files := make([]string, 0, len(c.Results))
+		for _, r := range c.Results {
+			formats = append(formats, r.Format)
+			files = append(files, r.File)
+		}
+		o.InputResultsFormat = strings.Join(formats, ",")
+		o.InputResultsFile = strings.Join(files, ",")
+	}
+	o.IgnoreRules = append(o.IgnoreRules, c.Ignore...)
+}
+
+// validateIgnoreRules checks the checks, probes, paths and expiry dates of the
+// ignore rules.
+func (o *Options) validateIgnoreRules() error {
+	names := map[string]bool{}
+	for name := range checks.GetAllWithExperimental() {
+		names[strings.ToLower(name)] = true
+	}
+	for _, r := range o.IgnoreRules {
+		switch {
+		case r.Check != "" && !names[strings.ToLower(r.Check)]:
+			return fmt.Errorf("%w: ignore rule: %w: %s", errInvalidConfig, errUnknownCheck, r.Check)
+		case r.Probe != "" && !slices.ContainsFunc(allProbes, func(p string) bool { return strings.EqualFold(p, r.Probe) }):
+			return fmt.Errorf("%w: ignore rule: %w: %s", errInvalidConfig, errUnknownProbe, r.Probe)
+		}
+		if _, err := path.Match(r.Path, ""); err != nil {
+			return fmt.Errorf("%w: ignore rule %s%s: path %q: %w", errInvalidConfig, r.Check, r.Probe, r.Path, err)
+		}
+		if _, err := r.expiry(); err != nil {
+			return fmt.Errorf("%w: ignore rule %s%s: expires %q: %w", errInvalidConfig, r.Check, r.Probe, r.Expires, err)
+		}
+	}
+	return nil
+}
+
+// Matches reports whether the rule suppresses an alert of the check or probe
+// with the given name, located in file.
+func (r *IgnoreRule) Matches(name, file string) bool {
+	if !strings.EqualFold(r.Check, name) && !strings.EqualFold(r.Probe, name) {
+		return false
+	}
+	if r.Path == "" {
+		return true
+	}
+	dir := strings.TrimSuffix(r.Path, "/")
+	if file == dir || strings.HasPrefix(file, dir+"/") {
+		return true
+	}
+	matched, err := path.Match(r.Path, file)
+	return err == nil && matched
+}
+
+// Expired reports whether the rule no longer applies at now. Rules without an
+// expiry date never expire.
+func (r *IgnoreRule) Expired(now time.Time) bool {
+	end, err := r.expiry()
+	return err == nil && !end.IsZero() && !now.Before(end)
+}
+
+// expiry returns the end of the expiry day of the rule, the zero time if it
+// has none.
+func (r *IgnoreRule) expiry() (time.Time, error) {
+	if r.Expires == "" {
+		return time.Time{}, nil
+	}
+	day, err := time.Parse(time.DateOnly, r.Expires)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing date: %w", err)
+	}
+	return day.AddDate(0, 0, 1), nil
+}
@@ -0,0 +1,251 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+const testConfig = `
+version: 1
+checks: [Code-Review, Token-Permissions]
+thresholds:
+  min_score: 7.5
+  fail_on_policy: true
+results:
+  - format: sarif
+    file: results.sarif
+  - format: json
+    file: results.json
+ignore:
+  - check: Token-Permissions
+    path: .github/workflows/release.yml
+    reason: needs contents write to publish releases
+`
+
+func TestParseConfig(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		contents string
+		wantErr  bool
+	}{
+		{
+			name:     "valid",
+			contents: testConfig,
+		},
+		{
+			name:     "minimal",
+			contents: "version: 1",
+		},
+		{
+			name:     "missing version",
+			contents: "checks: [Code-Review]",
+			wantErr:  true,
+		},
+		{
+			name:     "unsupported version",
+			contents: "version: 2",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown key",
+			contents: "version: 1\nchecks: [Code-Review]\nenable_checks: [Fuzzing]",
+			wantErr:  true,
+		},
+		{
+			name:     "unknown results format",
+			contents: "version: 1\nresults: [{format: xml, file: results.xml}]",
+			wantErr:  true,
+		},
+		{
+			name:     "min score out of range",
+			contents: "version: 1\nthresholds: {min_score: 11}",
+			wantErr:  true,
+		},
+		{
+			name:     "ignore rule without reason",
+			contents: "version: 1\nignore: [{check: Fuzzing}]",
+			wantErr:  true,
+		},
+		{
+			name:     "ignore rule with check and probe",
+			contents: "version: 1\nignore: [{check: Fuzzing, probe: fuzzed, reason: no fuzzing}]",
+			wantErr:  true,
+		},
+		{
+			name:     "not yaml",
+			contents: "version: [1",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := parseConfig([]byte(tt.contents))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseConfig() error: %v, wantErr %t", err, tt.wantErr)
+			}
+			if err != nil && !errors.Is(err, errInvalidConfig) {
+				t.Errorf("parseConfig() error: %v, want %v", err, errInvalidConfig)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // the test sets environment variables
+func TestApplyConfig(t *testing.T) {
+	c, err := parseConfig([]byte(testConfig))
+	if err != nil {
+		t.Fatalf("parseConfig(): %v", err)
+	}
+	t.Setenv(EnvInputMinScore, "5")
+	t.Setenv(EnvInputResultsFormat, "")
+	t.Setenv(EnvInputResultsFile, "")
+	t.Setenv(EnvInputEnabledChecks, "")
+	t.Setenv(EnvInputFailOnPolicy, "")
+
+	o := Options{InputMinScore: 5}
+	o.applyConfig(c)
+
+	want := Options{
+		InputEnabledChecks: "Code-Review,Token-Permissions",
+		InputMinScore:      5,
+		InputFailOnPolicy:  true,
+		InputResultsFormat: "sarif,json",
+		InputResultsFile:   "results.sarif,results.json",
+		IgnoreRules:        c.Ignore,
+	}
+	if diff := cmp.Diff(want, o, cmp.AllowUnexported(Options{})); diff != "" {
+		t.Errorf("applyConfig(): -want, +got:\n%s", diff)
+	}
+}
+
+func TestLoadConfig(t *testing.T) {
+	t.Parallel()
+	workspace := t.TempDir()
+	if err := os.WriteFile(filepath.Join(workspace, "scorecard.yml"), []byte("version: 1\nprobes: [fuzzed]"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name       string
+		configFile string
+		wantFile   string
+		wantErr    bool
+	}{
+		{
+			name: "no default config file",
+		},
+		{
+			name:       "explicit config file",
+			configFile: "scorecard.yml",
+			wantFile:   filepath.Join(workspace, "scorecard.yml"),
+		},
+		{
+			name:       "missing explicit config file",
+			configFile: "missing.yml",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			o := Options{GithubWorkspace: workspace, InputConfigFile: tt.configFile}
+			o.loadConfig()
+			if (o.configErr != nil) != tt.wantErr {
+				t.Fatalf("loadConfig() error: %v, wantErr %t", o.configErr, tt.wantErr)
+			}
+			if o.ConfigFile != tt.wantFile {
+				t.Errorf("ConfigFile = %q, want %q", o.ConfigFile, tt.wantFile)
+			}
+			if tt.wantErr {
+				if err := o.Validate(); !errors.Is(err, o.configErr) {
+					t.Errorf("Validate() error: %v, want %v", err, o.configErr)
+				}
+			}
+		})
+	}
+}
+
+func TestIgnoreRuleMatches(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name string
+		rule IgnoreRule
+		file string
+		want bool
+	}{
+		{
+			name: "check anywhere",
+			rule: IgnoreRule{Check: "Pinned-Dependencies"},
+			file: "Dockerfile",
+			want: true,
+		},
+		{
+			name: "check is case-insensitive",
+			rule: IgnoreRule{Check: "pinned-dependencies"},
+			file: "Dockerfile",
+			want: true,
+		},
+		{
+			name: "other check",
+			rule: IgnoreRule{Check: "Fuzzing"},
+			file: "Dockerfile",
+		},
+		{
+			name: "file",
+			rule: IgnoreRule{Check: "Pinned-Dependencies", Path: "docs/Dockerfile"},
+			file: "docs/Dockerfile",
+			want: true,
+		},
+		{
+			name: "directory",
+			rule: IgnoreRule{Check: "Pinned-Dependencies", Path: "docs/"},
+			file: "docs/examples/Dockerfile",
+			want: true,
+		},
+		{
+			name: "directory prefix is not a directory",
+			rule: IgnoreRule{Check: "Pinned-Dependencies", Path: "docs"},
+			file: "docs2/Dockerfile",
+		},
+		{
+			name: "glob",
+			rule: IgnoreRule{Probe: "hasDangerousWorkflowScriptInjection", Path: ".github/workflows/*.yml"},
+			file: ".github/workflows/ci.yml",
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			checkName := "Pinned-Dependencies"
+			if tt.rule.Probe != "" {
+				checkName = tt.rule.Probe
+			}
+			if got := tt.rule.Matches(checkName, tt.file); got != tt.want {
+				t.Errorf("Matches(%q, %q) = %t, want %t", checkName, tt.file, got, tt.want)
+			}
+		})
+	}
+}
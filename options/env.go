@@ -31,7 +31,10 @@ const (
 	EnvGithubEventName         = "GITHUB_EVENT_NAME"
 	EnvGithubRepository        = "GITHUB_REPOSITORY"
 	EnvGithubRef               = "GITHUB_REF"
+	EnvGithubSHA               = "GITHUB_SHA"
 	EnvGithubWorkspace         = "GITHUB_WORKSPACE"
+	EnvGithubStepSummary       = "GITHUB_STEP_SUMMARY"
+	EnvGithubOutput            = "GITHUB_OUTPUT"
 	EnvGithubAuthToken         = "GITHUB_AUTH_TOKEN" //nolint:gosec
 	EnvScorecardFork           = "SCORECARD_IS_FORK"
 	EnvScorecardPrivateRepo    = "SCORECARD_PRIVATE_REPOSITORY"
@@ -43,8 +46,35 @@ const (
 	EnvInputResultsFile            = "INPUT_RESULTS_FILE"
 	EnvInputResultsFormat          = "INPUT_RESULTS_FORMAT"
 	EnvInputPublishResults         = "INPUT_PUBLISH_RESULTS"
+	EnvInputPublishStrict          = "INPUT_PUBLISH_STRICT"
 	EnvInputFileMode               = "INPUT_FILE_MODE"
 	EnvInputInternalPublishBaseURL = "INPUT_INTERNAL_PUBLISH_BASE_URL"
+	EnvInputPolicyFile             = "INPUT_POLICY_FILE"
+	EnvInputMinScore               = "INPUT_MIN_SCORE"
+	EnvInputFailOnPolicy           = "INPUT_FAIL_ON_POLICY"
+	EnvInputCompareBaseline        = "INPUT_COMPARE_BASELINE"
+	EnvInputBaselineResultsFile    = "INPUT_BASELINE_RESULTS_FILE"
+	EnvInputDeltaFile              = "INPUT_DELTA_FILE"
+	EnvInputFailOnRegression       = "INPUT_FAIL_ON_REGRESSION"
+	EnvInputCommentOnPR            = "INPUT_COMMENT_ON_PR"
+	EnvInputComparePublished       = "INPUT_COMPARE_PUBLISHED"
+	EnvInputHistoryFile            = "INPUT_HISTORY_FILE"
+	EnvInputHistoryBranch          = "INPUT_HISTORY_BRANCH"
+	EnvInputFulcioURL              = "INPUT_FULCIO_URL"
+	EnvInputRekorURL               = "INPUT_REKOR_URL"
+	EnvInputOIDCIssuer             = "INPUT_OIDC_ISSUER"
+	EnvInputTUFMirror              = "INPUT_TUF_MIRROR"
+	EnvInputTUFRoot                = "INPUT_TUF_ROOT"
+	EnvInputSigningKey             = "INPUT_SIGNING_KEY"
+	EnvInputSigningKeyPassword     = "INPUT_SIGNING_KEY_PASSWORD" //nolint:gosec
+	EnvInputSignAttestation        = "INPUT_SIGN_ATTESTATION"
+	EnvInputEnabledChecks          = "INPUT_ENABLED_CHECKS"
+	EnvInputProbes                 = "INPUT_PROBES"
+	EnvInputConfigFile             = "INPUT_CONFIG_FILE"
+	EnvInputExemptionsFile         = "INPUT_EXEMPTIONS_FILE"
+
+	// EnvGitlabAuthToken is the token Scorecard reads GitLab projects with.
+	EnvGitlabAuthToken = "GITLAB_AUTH_TOKEN" //nolint:gosec
 )
 
 // Errors
@@ -52,6 +82,7 @@ const (
 var (
 	// Errors.
 	errEmptyGitHubAuthToken = errEnvVarIsEmptyWithKey(EnvGithubAuthToken)
+	errEmptyGitLabAuthToken = errEnvVarIsEmptyWithKey(EnvGitlabAuthToken)
 
 	errEnvVarIsEmpty = errors.New("env var is empty")
 )
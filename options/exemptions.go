@@ -0,0 +1,84 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+// DefaultExemptionsFile is the exemptions file read from the workspace when
+// the exemptions_file input is unset.
+const DefaultExemptionsFile = ".github/scorecard-exemptions.yml"
+
+// Exemptions is the exemptions file: accepted risks, each with an expiry date.
+type Exemptions struct {
+	Version    int          `json:"version"`
+	Exemptions []IgnoreRule `json:"exemptions"`
+}
+
+// LoadExemptions reads an exemptions file and validates it against the schema.
+func LoadExemptions(path string) (*Exemptions, error) {
+	contents, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading exemptions file: %w", err)
+	}
+	var e Exemptions
+	if err := decodeYAML(contents, exemptionsSchemaURL, &e); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// loadExemptions adds the exemptions file, if any, to the ignore rules. Errors
+// are kept for Validate to report.
+func (o *Options) loadExemptions() {
+	path, required := o.workspaceFile(o.InputExemptionsFile, DefaultExemptionsFile)
+	if _, err := os.Stat(path); errors.Is(err, os.ErrNotExist) && !required {
+		return
+	}
+	e, err := LoadExemptions(path)
+	if err != nil {
+		o.configErr = errors.Join(o.configErr, fmt.Errorf("loading %s: %w", path, err))
+		return
+	}
+	o.ExemptionsFile = path
+	o.IgnoreRules = append(o.IgnoreRules, e.Exemptions...)
+}
+
+// ActiveIgnoreRules returns the ignore rules that have not expired at now.
+func (o *Options) ActiveIgnoreRules(now time.Time) []IgnoreRule {
+	var active []IgnoreRule
+	for _, r := range o.IgnoreRules {
+		if !r.Expired(now) {
+			active = append(active, r)
+		}
+	}
+	return active
+}
+
+// ExpiredIgnoreRules returns the ignore rules that have expired at now. Their
+// alerts are reported again.
+func (o *Options) ExpiredIgnoreRules(now time.Time) []IgnoreRule {
+	var expired []IgnoreRule
+	for _, r := range o.IgnoreRules {
+		if r.Expired(now) {
+			expired = append(expired, r)
+		}
+	}
+	return expired
+}
@@ -0,0 +1,187 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestLoadExemptions(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name     string
+		contents string
+		want     []IgnoreRule
+		wantErr  bool
+	}{
+		{
+			name: "valid",
+			contents: `
+version: 1
+exemptions:
+  - check: Signed-Releases
+    reason: internal tool, not released
+    expires: 2026-12-31
+  - probe: fuzzed
+    path: tools/
+    reason: no untrusted input
+    expires: "2027-01-15"
+`,
+			want: []IgnoreRule{
+				{Check: "Signed-Releases", Reason: "internal tool, not released", Expires: "2026-12-31"},
+				{Probe: "fuzzed", Path: "tools/", Reason: "no untrusted input", Expires: "2027-01-15"},
+			},
+		},
+		{
+			name:     "exemption without expiry date",
+			contents: "version: 1\nexemptions: [{check: Signed-Releases, reason: internal tool}]",
+			wantErr:  true,
+		},
+		{
+			name:     "malformed expiry date",
+			contents: "version: 1\nexemptions: [{check: Signed-Releases, reason: internal tool, expires: 12/31/2026}]",
+			wantErr:  true,
+		},
+		{
+			name:     "missing exemptions",
+			contents: "version: 1",
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			path := filepath.Join(t.TempDir(), "exemptions.yml")
+			if err := os.WriteFile(path, []byte(tt.contents), 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := LoadExemptions(path)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("LoadExemptions() error: %v, wantErr %t", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, errInvalidConfig) {
+					t.Errorf("LoadExemptions() error: %v, want %v", err, errInvalidConfig)
+				}
+				return
+			}
+			if diff := cmp.Diff(tt.want, got.Exemptions); diff != "" {
+				t.Errorf("exemptions: -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestIgnoreRuleExpired(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		expires string
+		now     time.Time
+		want    bool
+	}{
+		{
+			name: "no expiry date",
+			now:  time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "before the expiry date",
+			expires: "2026-12-31",
+			now:     time.Date(2026, 12, 30, 12, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "on the expiry date",
+			expires: "2026-12-31",
+			now:     time.Date(2026, 12, 31, 23, 59, 0, 0, time.UTC),
+		},
+		{
+			name:    "after the expiry date",
+			expires: "2026-12-31",
+			now:     time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			want:    true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			r := IgnoreRule{Check: "Signed-Releases", Expires: tt.expires}
+			if got := r.Expired(tt.now); got != tt.want {
+				t.Errorf("Expired(%v) = %t, want %t", tt.now, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadExemptionsOptions(t *testing.T) {
+	t.Parallel()
+	workspace := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(workspace, ".github"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	contents := `
+version: 1
+exemptions:
+  - check: Signed-Releases
+    reason: internal tool, not released
+    expires: 2026-12-31
+  - check: Fuzzing
+    reason: fuzzing is planned
+    expires: 2026-06-30
+`
+	if err := os.WriteFile(filepath.Join(workspace, DefaultExemptionsFile), []byte(contents), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	o := Options{
+		GithubWorkspace: workspace,
+		IgnoreRules:     []IgnoreRule{{Check: "Pinned-Dependencies", Path: "docs/", Reason: "docs images"}},
+	}
+	o.loadExemptions()
+	if o.configErr != nil {
+		t.Fatalf("loadExemptions(): %v", o.configErr)
+	}
+	if want := filepath.Join(workspace, DefaultExemptionsFile); o.ExemptionsFile != want {
+		t.Errorf("ExemptionsFile = %q, want %q", o.ExemptionsFile, want)
+	}
+
+	now := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	names := func(rules []IgnoreRule) []string {
+		var names []string
+		for _, r := range rules {
+			names = append(names, r.Check)
+		}
+		return names
+	}
+	if diff := cmp.Diff([]string{"Pinned-Dependencies", "Signed-Releases"}, names(o.ActiveIgnoreRules(now))); diff != "" {
+		t.Errorf("ActiveIgnoreRules(): -want, +got:\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"Fuzzing"}, names(o.ExpiredIgnoreRules(now))); diff != "" {
+		t.Errorf("ExpiredIgnoreRules(): -want, +got:\n%s", diff)
+	}
+
+	missing := Options{GithubWorkspace: workspace, InputExemptionsFile: "missing.yml"}
+	missing.loadExemptions()
+	if missing.configErr == nil {
+		t.Error("loadExemptions() with a missing exemptions file: got no error")
+	}
+}
@@ -0,0 +1,40 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import "errors"
+
+// DefaultHistoryFile is the history file written to the history branch when
+// the history_file input is unset.
+const DefaultHistoryFile = "scorecard-history.jsonl"
+
+var (
+	errHistoryWithProbes        = errors.New("score history cannot be used with probes")
+	errHistoryBranchUnsupported = errors.New("history_branch is only supported on GitHub")
+)
+
+// HistoryEnabled reports whether the run is appended to the score history.
+func (o *Options) HistoryEnabled() bool {
+	return o.InputHistoryFile != "" || o.InputHistoryBranch != ""
+}
+
+// HistoryFile returns the path of the score history, relative to the
+// workspace or to the root of the history branch.
+func (o *Options) HistoryFile() string {
+	if o.InputHistoryFile == "" {
+		return DefaultHistoryFile
+	}
+	return o.InputHistoryFile
+}
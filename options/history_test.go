@@ -0,0 +1,58 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import "testing"
+
+func TestHistoryFile(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name        string
+		file        string
+		branch      string
+		wantEnabled bool
+		wantFile    string
+	}{
+		{
+			name:     "disabled",
+			wantFile: DefaultHistoryFile,
+		},
+		{
+			name:        "file",
+			file:        ".scorecard/history.jsonl",
+			wantEnabled: true,
+			wantFile:    ".scorecard/history.jsonl",
+		},
+		{
+			name:        "branch with the default file",
+			branch:      "scorecard-history",
+			wantEnabled: true,
+			wantFile:    DefaultHistoryFile,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			o := Options{InputHistoryFile: tt.file, InputHistoryBranch: tt.branch}
+			if got := o.HistoryEnabled(); got != tt.wantEnabled {
+				t.Errorf("HistoryEnabled() = %t, want %t", got, tt.wantEnabled)
+			}
+			if got := o.HistoryFile(); got != tt.wantFile {
+				t.Errorf("HistoryFile() = %q, want %q", got, tt.wantFile)
+			}
+		})
+	}
+}
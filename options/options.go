@@ -17,6 +17,7 @@ package options
 import (
 	"errors"
 	"fmt"
+	"net/url"
 	"os"
 	"strconv"
 	"strings"
@@ -32,6 +33,8 @@ const (
 	defaultScorecardPolicyFile = "/policy.yml"
 	trueStr                    = "true"
 	formatSarif                = scopts.FormatSarif
+	formatMarkdown             = "markdown"
+	formatCodeQuality          = "codequality"
 
 	pullRequestEvent      = "pull_request"
 	pushEvent             = "push"
@@ -40,10 +43,13 @@ const (
 
 var (
 	// Errors.
-	errGithubEventPathEmpty       = errors.New("GitHub event path is empty")
-	errResultsPathEmpty           = errors.New("results path is empty")
-	errGitHubRepoInfoUnavailable  = errors.New("GitHub repo info inaccessible")
-	errOnlyDefaultBranchSupported = errors.New("only default branch is supported")
+	errGithubEventPathEmpty      = errors.New("GitHub event path is empty")
+	errResultsPathEmpty          = errors.New("results path is empty")
+	errGitHubRepoInfoUnavailable = errors.New("GitHub repo info inaccessible")
+	errResultsFileMismatch       = errors.New("number of results files does not match number of results formats")
+	errInvalidSigstoreURL        = errors.New("invalid Sigstore URL")
+	errSigningKeyWithPublish     = errors.New("signing_key cannot be used with publish_results")
+	errSigstoreWithPublish       = errors.New("custom Sigstore endpoints cannot be used with publish_results")
 )
 
 // Options are options for running scorecard via GitHub Actions.
@@ -51,8 +57,17 @@ type Options struct {
 	// Scorecard options.
 	ScorecardOpts *scopts.Options
 
+	// CI is the CI system the action runs in, e.g. CIGitLab.
+	CI string
+
 	// Scorecard command-line options.
 	EnabledChecks string `env:"ENABLED_CHECKS"`
+	// InputEnabledChecks are the checks to run, comma-separated. It takes
+	// precedence over EnabledChecks.
+	InputEnabledChecks string `env:"INPUT_ENABLED_CHECKS"`
+	// InputProbes are the probes to run instead of checks, comma-separated,
+	// or "all".
+	InputProbes string `env:"INPUT_PROBES"`
 
 	// Scorecard checks.
 	EnableLicense           string `env:"ENABLE_LICENSE"`
@@ -64,11 +79,18 @@ type Options struct {
 	GithubEventName  string `env:"GITHUB_EVENT_NAME"`
 	GithubEventPath  string `env:"GITHUB_EVENT_PATH"`
 	GithubRef        string `env:"GITHUB_REF"`
+	GithubSHA        string `env:"GITHUB_SHA"`
 	GithubRepository string `env:"GITHUB_REPOSITORY"`
 	GithubWorkspace  string `env:"GITHUB_WORKSPACE"`
 	GithubAPIURL     string `env:"GITHUB_API_URL"`
+	// GithubStepSummary is the job summary file the markdown results are appended to.
+	GithubStepSummary string `env:"GITHUB_STEP_SUMMARY"`
+	// GithubOutput is the file step outputs are appended to.
+	GithubOutput string `env:"GITHUB_OUTPUT"`
 
 	DefaultBranch string `env:"SCORECARD_DEFAULT_BRANCH"`
+	// PullRequestNumber is read from the event file of pull request events.
+	PullRequestNumber int
 	// TODO(options): This may be better as a bool
 	IsForkStr string `env:"SCORECARD_IS_FORK"`
 	// TODO(options): This may be better as a bool
@@ -78,8 +100,72 @@ type Options struct {
 	InputResultsFile   string `env:"INPUT_RESULTS_FILE"`
 	InputResultsFormat string `env:"INPUT_RESULTS_FORMAT"`
 	InputFileMode      string `env:"INPUT_FILE_MODE"`
+	InputPolicyFile    string `env:"INPUT_POLICY_FILE"`
+
+	// Threshold inputs. A zero minimum score disables the aggregate check.
+	InputMinScore     float64 `env:"INPUT_MIN_SCORE"`
+	InputFailOnPolicy bool    `env:"INPUT_FAIL_ON_POLICY"`
+
+	// Pull request diff inputs. Without a baseline results file, the default
+	// branch is scored remotely.
+	InputCompareBaseline     bool   `env:"INPUT_COMPARE_BASELINE"`
+	InputBaselineResultsFile string `env:"INPUT_BASELINE_RESULTS_FILE"`
+	InputDeltaFile           string `env:"INPUT_DELTA_FILE" envDefault:"scorecard-delta.json"`
+	InputFailOnRegression    bool   `env:"INPUT_FAIL_ON_REGRESSION"`
+
+	// InputCommentOnPR creates or updates a results comment on pull requests.
+	InputCommentOnPR bool `env:"INPUT_COMMENT_ON_PR"`
+
+	// InputComparePublished compares the results against those published to
+	// the Scorecard API, and checks that publishing them succeeded.
+	InputComparePublished bool `env:"INPUT_COMPARE_PUBLISHED"`
+
+	// Score history inputs. Each run appends to the history file in the
+	// workspace or, if a history branch is set, in that branch.
+	InputHistoryFile   string `env:"INPUT_HISTORY_FILE"`
+	InputHistoryBranch string `env:"INPUT_HISTORY_BRANCH"`
+
+	// Sigstore deployment used to sign published results. Empty values use
+	// the public-good instance.
+	InputFulcioURL  string `env:"INPUT_FULCIO_URL"`
+	InputRekorURL   string `env:"INPUT_REKOR_URL"`
+	InputOIDCIssuer string `env:"INPUT_OIDC_ISSUER"`
+	InputTUFMirror  string `env:"INPUT_TUF_MIRROR"`
+	InputTUFRoot    string `env:"INPUT_TUF_ROOT"`
+
+	// Key-based signing: a KMS URI, PEM key file or PEM contents. Results are
+	// signed with the key instead of publishing them.
+	InputSigningKey         string `env:"INPUT_SIGNING_KEY"`
+	InputSigningKeyPassword string `env:"INPUT_SIGNING_KEY_PASSWORD"`
+
+	// InputSignAttestation signs the results as an in-toto attestation, with
+	// the signing key if set, else keylessly.
+	InputSignAttestation bool `env:"INPUT_SIGN_ATTESTATION"`
+
+	// InputConfigFile is the configuration file, DefaultConfigFile if unset.
+	InputConfigFile string `env:"INPUT_CONFIG_FILE"`
+	// ConfigFile is the configuration file that was loaded, if any.
+	ConfigFile string
+	// InputExemptionsFile is the exemptions file, DefaultExemptionsFile if unset.
+	InputExemptionsFile string `env:"INPUT_EXEMPTIONS_FILE"`
+	// ExemptionsFile is the exemptions file that was loaded, if any.
+	ExemptionsFile string
+	// IgnoreRules suppress alerts in the SARIF results, from the configuration
+	// and exemptions files.
+	IgnoreRules []IgnoreRule
+	// configErr is the error loading the configuration or exemptions file,
+	// reported by Validate.
+	configErr error
 
 	PublishResults bool
+	// InputPublishStrict fails the run when publishing the results fails.
+	InputPublishStrict bool `env:"INPUT_PUBLISH_STRICT"`
+}
+
+// ResultsOutput is a single results artifact: a format and the file it is written to.
+type ResultsOutput struct {
+	Format string `json:"format"`
+	File   string `json:"file"`
 }
 
 // New creates a new options set for running scorecard via GitHub Actions.
@@ -88,16 +174,13 @@ func New() (*Options, error) {
 	if err := env.Parse(opts); err != nil {
 		return opts, fmt.Errorf("parsing entrypoint env vars: %w", err)
 	}
-	// GITHUB_AUTH_TOKEN
-	// Needs to be set *before* setRepoInfo() is invoked.
-	// setRepoInfo() uses the GITHUB_AUTH_TOKEN env for querying the REST API.
-	if _, tokenSet := os.LookupEnv(EnvGithubAuthToken); !tokenSet {
-		inputToken := os.Getenv(EnvInputRepoToken)
-		os.Setenv(EnvGithubAuthToken, inputToken)
-	}
-	if err := opts.setRepoInfo(); err != nil {
-		return opts, fmt.Errorf("parsing repo info: %w", err)
+	ci := detectCI()
+	opts.CI = ci.Name()
+	if err := ci.Apply(opts); err != nil {
+		return opts, err
 	}
+	opts.loadConfig()
+	opts.loadExemptions()
 	opts.setScorecardOpts()
 	opts.setPublishResults()
 	return opts, nil
@@ -105,8 +188,21 @@ func New() (*Options, error) {
 
 // Validate validates the scorecard configuration.
 func (o *Options) Validate() error {
-	fmt.Println("EnvGithubAuthToken:", EnvGithubAuthToken, os.Getenv(EnvGithubAuthToken))
-	if os.Getenv(EnvGithubAuthToken) == "" {
+	if o.configErr != nil {
+		fmt.Printf("::error ::Invalid configuration file: %v\n", o.configErr)
+		return o.configErr
+	}
+	if err := o.validateIgnoreRules(); err != nil {
+		fmt.Printf("::error ::Invalid configuration file: %v\n", err)
+		return err
+	}
+	if o.CI == CIGitLab {
+		if os.Getenv(EnvGitlabAuthToken) == "" {
+			fmt.Printf("%s variable is empty.\n", EnvGitlabAuthToken)
+			fmt.Printf("Please set it as a CI/CD variable to a token with the read_api scope.\n")
+			return errEmptyGitLabAuthToken
+		}
+	} else if os.Getenv(EnvGithubAuthToken) == "" {
 		fmt.Printf("%s variable is empty.\n", EnvGithubAuthToken)
 		if o.IsForkStr == trueStr {
 			fmt.Printf("We have detected you are running on a fork.\n")
@@ -119,12 +215,37 @@ func (o *Options) Validate() error {
 		return errEmptyGitHubAuthToken
 	}
 
-	if !o.isPullRequestEvent() &&
-		!o.isDefaultBranch() {
-		fmt.Printf("%s not supported with %s event.\n", o.GithubRef, o.GithubEventName)
-		fmt.Printf("::error ::Only the default branch %s is supported.\n", o.DefaultBranch)
-
-		return errOnlyDefaultBranchSupported
+	enabledChecks, err := o.Checks()
+	if err != nil {
+		fmt.Printf("::error ::Invalid enabled_checks: %v\n", err)
+		return err
+	}
+	if _, err := o.Probes(); err != nil {
+		fmt.Printf("::error ::Invalid probes: %v\n", err)
+		return err
+	}
+	if o.InputProbes != "" && os.Getenv(EnvInputPublishResults) == trueStr {
+		fmt.Printf("::error ::Only check results can be published, unset probes or publish_results.\n")
+		return errProbesWithPublish
+	}
+	if enabledChecks != nil && os.Getenv(EnvInputPublishResults) == trueStr {
+		// Skipped checks are left out of the aggregate score, which would make
+		// the published score partial.
+		fmt.Printf("::error ::Only full check results can be published, unset enabled_checks, the ENABLE_* toggles or publish_results.\n") //nolint:lll
+		return errChecksWithPublish
+	}
+	if o.customSigstore() && os.Getenv(EnvInputPublishResults) == trueStr {
+		// The Scorecard API only accepts results signed with public-good Sigstore.
+		fmt.Printf("::error ::Published results are signed with public-good Sigstore, unset the Sigstore inputs or publish_results.\n") //nolint:lll
+		return errSigstoreWithPublish
+	}
+	if o.HistoryEnabled() && o.InputProbes != "" {
+		fmt.Printf("::error ::The score history needs check results, unset probes or the history inputs.\n")
+		return errHistoryWithProbes
+	}
+	if o.InputHistoryBranch != "" && o.CI == CIGitLab {
+		fmt.Printf("::error ::history_branch is only supported on GitHub, use history_file instead.\n")
+		return errHistoryBranchUnsupported
 	}
 	if err := o.ScorecardOpts.Validate(); err != nil {
 		return fmt.Errorf("validating scorecard options: %w", err)
@@ -133,9 +254,58 @@ func (o *Options) Validate() error {
 		// TODO(test): Reassess test case for this code path
 		return errResultsPathEmpty
 	}
+	if _, err := o.ResultsOutputs(); err != nil {
+		return err
+	}
+	if o.InputSigningKey != "" && os.Getenv(EnvInputPublishResults) == trueStr {
+		fmt.Printf("::error ::Published results are signed keylessly, unset signing_key or publish_results.\n")
+		return errSigningKeyWithPublish
+	}
+	sigstoreURLs := []struct{ input, value string }{
+		{"fulcio_url", o.InputFulcioURL},
+		{"rekor_url", o.InputRekorURL},
+		{"oidc_issuer", o.InputOIDCIssuer},
+		{"tuf_mirror", o.InputTUFMirror},
+	}
+	for _, u := range sigstoreURLs {
+		if u.value != "" && !isHTTPURL(u.value) {
+			return fmt.Errorf("%w: %s: %q", errInvalidSigstoreURL, u.input, u.value)
+		}
+	}
 	return nil
 }
 
+// customSigstore reports whether any input selects a Sigstore deployment other
+// than public-good.
+func (o *Options) customSigstore() bool {
+	return o.InputFulcioURL != "" || o.InputRekorURL != "" || o.InputOIDCIssuer != "" ||
+		o.InputTUFMirror != "" || o.InputTUFRoot != ""
+}
+
+// isHTTPURL reports whether s is an absolute http(s) URL.
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// ResultsOutputs pairs each of the comma-separated results formats with the
+// results file at the same position.
+func (o *Options) ResultsOutputs() ([]ResultsOutput, error) {
+	formats := splitList(o.InputResultsFormat)
+	files := splitList(o.InputResultsFile)
+	if len(formats) != len(files) {
+		return nil, fmt.Errorf("%w: %d formats, %d files", errResultsFileMismatch, len(formats), len(files))
+	}
+	outputs := make([]ResultsOutput, 0, len(formats))
+	for i := range formats {
+		outputs = append(outputs, ResultsOutput{
+			Format: formats[i],
+			File:   files[i],
+		})
+	}
+	return outputs, nil
+}
+
 // Print is a function to print options.
 func (o *Options) Print() {
 	// Scorecard options
@@ -145,14 +315,50 @@ func (o *Options) Print() {
 	fmt.Printf("  Local: %s\n", o.ScorecardOpts.Local)
 	fmt.Printf("  Format: %s\n", o.ScorecardOpts.Format)
 	fmt.Printf("  Policy file: %s\n", o.ScorecardOpts.PolicyFile)
+	if o.ConfigFile != "" {
+		fmt.Printf("  Config file: %s\n", o.ConfigFile)
+	}
+	if o.ExemptionsFile != "" {
+		fmt.Printf("  Exemptions file: %s\n", o.ExemptionsFile)
+	}
+	if len(o.ScorecardOpts.ChecksToRun) > 0 {
+		fmt.Printf("  Checks: %s\n", strings.Join(o.ScorecardOpts.ChecksToRun, ", "))
+	}
+	if len(o.ScorecardOpts.ProbesToRun) > 0 {
+		fmt.Printf("  Probes: %s\n", strings.Join(o.ScorecardOpts.ProbesToRun, ", "))
+	}
 	fmt.Println()
 	fmt.Println("Event / repo information:")
+	fmt.Printf("  CI: %s\n", o.CI)
 	fmt.Printf("  Event file: %s\n", o.GithubEventPath)
 	fmt.Printf("  Event name: %s\n", o.GithubEventName)
+	fmt.Printf("  Git ref: %s\n", o.GithubRef)
 	fmt.Printf("  Fork repository: %s\n", o.IsForkStr)
 	fmt.Printf("  Private repository: %s\n", o.PrivateRepoStr)
 	fmt.Printf("  Publication enabled: %+v\n", o.PublishResults)
+	fmt.Printf("  Strict publication: %+v\n", o.InputPublishStrict)
 	fmt.Printf("  Default branch: %s\n", o.DefaultBranch)
+	fmt.Println()
+	fmt.Println("Thresholds:")
+	fmt.Printf("  Minimum aggregate score: %.1f\n", o.InputMinScore)
+	fmt.Printf("  Fail on policy: %+v\n", o.InputFailOnPolicy)
+	fmt.Printf("  Compare to baseline: %+v\n", o.InputCompareBaseline)
+	fmt.Printf("  Fail on regression: %+v\n", o.InputFailOnRegression)
+	fmt.Printf("  Comment on pull request: %+v\n", o.InputCommentOnPR)
+	fmt.Printf("  Compare to published results: %+v\n", o.InputComparePublished)
+	if o.HistoryEnabled() {
+		fmt.Printf("  History file: %s\n", o.HistoryFile())
+		fmt.Printf("  History branch: %s\n", o.InputHistoryBranch)
+	}
+	fmt.Println()
+	fmt.Println("Sigstore:")
+	fmt.Printf("  Fulcio URL: %s\n", o.InputFulcioURL)
+	fmt.Printf("  Rekor URL: %s\n", o.InputRekorURL)
+	fmt.Printf("  OIDC issuer: %s\n", o.InputOIDCIssuer)
+	fmt.Printf("  TUF mirror: %s\n", o.InputTUFMirror)
+	fmt.Printf("  TUF root: %s\n", o.InputTUFRoot)
+	fmt.Printf("  Key-based signing: %+v\n", o.InputSigningKey != "")
+	fmt.Printf("  Sign attestation: %+v\n", o.InputSignAttestation)
 }
 
 func (o *Options) setScorecardOpts() {
@@ -181,20 +387,45 @@ func (o *Options) setScorecardOpts() {
 	os.Setenv(scopts.EnvVarEnableSarif, trueStr)
 	o.ScorecardOpts.EnableSarif = true
 	o.ScorecardOpts.Format = formatSarif
-	if o.InputResultsFormat != "" {
-		o.ScorecardOpts.Format = o.InputResultsFormat
+	if o.InputPolicyFile != "" {
+		o.ScorecardOpts.PolicyFile = o.InputPolicyFile
+	}
+	// Use the first requested format scorecard knows about. markdown and
+	// codequality are rendered by the action itself, so scorecard keeps its
+	// default for them.
+	for _, format := range splitList(o.InputResultsFormat) {
+		if format != "" && !strings.EqualFold(format, formatMarkdown) &&
+			!strings.EqualFold(format, formatCodeQuality) {
+			o.ScorecardOpts.Format = format
+			break
+		}
 	}
 	if o.ScorecardOpts.Format == formatSarif && o.ScorecardOpts.PolicyFile == "" {
 		// TODO(policy): Should we default or error here?
 		o.ScorecardOpts.PolicyFile = defaultScorecardPolicyFile
 	}
 
+	// --checks=
+	// Invalid checks are reported by Validate.
+	if checks, err := o.Checks(); err == nil {
+		o.ScorecardOpts.ChecksToRun = checks
+	}
+
+	// --probes=
+	if probes, err := o.Probes(); err == nil {
+		o.ScorecardOpts.ProbesToRun = probes
+	}
+
 	// --show-details
 	o.ScorecardOpts.ShowDetails = true
 
 	// --commit=
-	// TODO(scorecard): Reset commit options. Fix this in scorecard.
+	// The default branch is scored at its head. Other refs, e.g. release
+	// branches and tags, are scored at the commit of the run.
 	o.ScorecardOpts.Commit = scopts.DefaultCommit
+	if !o.isPullRequestEvent() && !o.IsDefaultBranch() && o.GithubSHA != "" {
+		o.ScorecardOpts.Commit = o.GithubSHA
+	}
 
 	// --out-file=
 	if o.ScorecardOpts.ResultsFile == "" {
@@ -207,6 +438,10 @@ func (o *Options) setScorecardOpts() {
 func (o *Options) setPublishResults() {
 	inputVal := o.PublishResults
 	o.PublishResults = false
+	if o.CI == CIGitLab {
+		// The Scorecard API only accepts results signed in GitHub Actions.
+		return
+	}
 	privateRepo, err := strconv.ParseBool(o.PrivateRepoStr)
 	if err != nil {
 		// TODO(options): Consider making this an error.
@@ -246,6 +481,9 @@ func (o *Options) setRepoInfo() error {
 }
 
 func (o *Options) parseFromRepoInfo(repoInfo github.RepoInfo) bool {
+	if repoInfo.Number != 0 {
+		o.PullRequestNumber = repoInfo.Number
+	}
 	if repoInfo.Repo.DefaultBranch == nil &&
 		repoInfo.Repo.Fork == nil &&
 		repoInfo.Repo.Private == nil {
@@ -263,10 +501,20 @@ func (o *Options) parseFromRepoInfo(repoInfo github.RepoInfo) bool {
 	return true
 }
 
+// splitList splits a comma-separated input, trimming whitespace around each element.
+func splitList(s string) []string {
+	elems := strings.Split(s, ",")
+	for i := range elems {
+		elems[i] = strings.TrimSpace(elems[i])
+	}
+	return elems
+}
+
 func (o *Options) isPullRequestEvent() bool {
 	return strings.HasPrefix(o.GithubEventName, pullRequestEvent)
 }
 
-func (o *Options) isDefaultBranch() bool {
+// IsDefaultBranch reports whether the run is for the default branch.
+func (o *Options) IsDefaultBranch() bool {
 	return o.GithubRef == fmt.Sprintf("refs/heads/%s", o.DefaultBranch)
 }
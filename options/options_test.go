@@ -16,6 +16,7 @@
 package options
 
 import (
+	"errors"
 	"os"
 	"testing"
 
@@ -27,6 +28,7 @@ const (
 	testRepo        = "good/repo"
 	testResultsFile = "results.sarif"
 	testToken       = "test-token"
+	testSHA         = "68bc59901773ab4c051dfcea0cc4201a1567ab32"
 
 	githubEventPathNonFork   = "testdata/non-fork.json"
 	githubEventPathFork      = "testdata/fork.json"
@@ -55,6 +57,7 @@ func TestNew(t *testing.T) {
 		githubEventPath  string
 		githubEventName  string
 		githubRef        string
+		githubSHA        string
 		repo             string
 		resultsFile      string
 		resultsFormat    string
@@ -108,6 +111,28 @@ func TestNew(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name:            "SuccessFormatMarkdown",
+			githubEventPath: githubEventPathNonFork,
+			githubEventName: pushEvent,
+			githubRef:       "refs/heads/main",
+			repo:            testRepo,
+			resultsFormat:   "markdown",
+			resultsFile:     testResultsFile,
+			fileMode:        options.FileModeArchive,
+			want: fields{
+				EnableSarif: true,
+				Format:      formatSarif,
+				PolicyFile:  defaultScorecardPolicyFile,
+				ResultsFile: testResultsFile,
+				Commit:      options.DefaultCommit,
+				LogLevel:    options.DefaultLogLevel,
+				Repo:        testRepo,
+				ShowDetails: true,
+				FileMode:    options.FileModeArchive,
+			},
+			wantErr: false,
+		},
 		{
 			name:            "SuccessFileModeGit",
 			githubEventPath: githubEventPathNonFork,
@@ -233,10 +258,57 @@ func TestNew(t *testing.T) {
 			wantErr: true,
 		},
 		{
-			name:            "FailureBranchIsntMain",
+			name:            "SuccessOtherBranch",
 			githubEventPath: githubEventPathNonFork,
 			githubEventName: pushEvent,
 			githubRef:       "refs/heads/other-branch",
+			githubSHA:       testSHA,
+			repo:            testRepo,
+			resultsFormat:   "sarif",
+			resultsFile:     testResultsFile,
+			fileMode:        options.FileModeArchive,
+			want: fields{
+				EnableSarif: true,
+				Format:      formatSarif,
+				PolicyFile:  defaultScorecardPolicyFile,
+				ResultsFile: testResultsFile,
+				Commit:      testSHA,
+				LogLevel:    options.DefaultLogLevel,
+				Repo:        testRepo,
+				ShowDetails: true,
+				FileMode:    options.FileModeArchive,
+			},
+			wantErr: false,
+		},
+		{
+			name:            "SuccessTag",
+			githubEventPath: githubEventPathNonFork,
+			githubEventName: pushEvent,
+			githubRef:       "refs/tags/v1.0.0",
+			githubSHA:       testSHA,
+			repo:            testRepo,
+			resultsFormat:   "sarif",
+			resultsFile:     testResultsFile,
+			fileMode:        options.FileModeArchive,
+			want: fields{
+				EnableSarif: true,
+				Format:      formatSarif,
+				PolicyFile:  defaultScorecardPolicyFile,
+				ResultsFile: testResultsFile,
+				Commit:      testSHA,
+				LogLevel:    options.DefaultLogLevel,
+				Repo:        testRepo,
+				ShowDetails: true,
+				FileMode:    options.FileModeArchive,
+			},
+			wantErr: false,
+		},
+		{
+			name:            "SuccessDefaultBranchAtHead",
+			githubEventPath: githubEventPathNonFork,
+			githubEventName: pushEvent,
+			githubRef:       "refs/heads/main",
+			githubSHA:       testSHA,
 			repo:            testRepo,
 			resultsFormat:   "sarif",
 			resultsFile:     testResultsFile,
@@ -252,7 +324,7 @@ func TestNew(t *testing.T) {
 				ShowDetails: true,
 				FileMode:    options.FileModeArchive,
 			},
-			wantErr: true,
+			wantErr: false,
 		},
 	}
 	for _, tt := range tests {
@@ -277,6 +349,8 @@ func TestNew(t *testing.T) {
 			os.Setenv(EnvGithubRef, tt.githubRef)
 			defer os.Unsetenv(EnvGithubRef)
 
+			t.Setenv(EnvGithubSHA, tt.githubSHA)
+
 			os.Setenv(EnvGithubRepository, tt.repo)
 			defer os.Unsetenv(EnvGithubRepository)
 
@@ -445,6 +519,7 @@ func TestPrint(t *testing.T) {
 func TestSetPublishResults(t *testing.T) {
 	tests := []struct {
 		name        string
+		ci          string
 		privateRepo string
 		userInput   bool
 		want        bool
@@ -464,11 +539,26 @@ func TestSetPublishResults(t *testing.T) {
 			privateRepo: "invalid-value",
 			want:        false,
 		},
+		{
+			name:        "InputTruePublicRepo",
+			privateRepo: "false",
+			userInput:   true,
+			want:        true,
+		},
+		{
+			name:        "InputTrueGitLab",
+			ci:          CIGitLab,
+			privateRepo: "false",
+			userInput:   true,
+			want:        false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			opts := &Options{
-				ScorecardOpts: options.New(),
+				ScorecardOpts:  options.New(),
+				CI:             tt.ci,
+				PublishResults: tt.userInput,
 			}
 			opts.PrivateRepoStr = tt.privateRepo
 
@@ -481,3 +571,120 @@ func TestSetPublishResults(t *testing.T) {
 		})
 	}
 }
+
+func TestResultsOutputs(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		file    string
+		want    []ResultsOutput
+		wantErr bool
+	}{
+		{
+			name:   "SingleFormat",
+			format: "sarif",
+			file:   "results.sarif",
+			want:   []ResultsOutput{{Format: "sarif", File: "results.sarif"}},
+		},
+		{
+			name: "DefaultFormat",
+			file: "results.sarif",
+			want: []ResultsOutput{{Format: "", File: "results.sarif"}},
+		},
+		{
+			name:   "MultipleFormats",
+			format: "sarif, json,markdown",
+			file:   "results.sarif,results.json, results.md",
+			want: []ResultsOutput{
+				{Format: "sarif", File: "results.sarif"},
+				{Format: "json", File: "results.json"},
+				{Format: "markdown", File: "results.md"},
+			},
+		},
+		{
+			name:    "MismatchedFiles",
+			format:  "sarif,json",
+			file:    "results.sarif",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			o := &Options{
+				InputResultsFormat: tt.format,
+				InputResultsFile:   tt.file,
+			}
+			got, err := o.ResultsOutputs()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ResultsOutputs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !cmp.Equal(tt.want, got) {
+				t.Errorf("ResultsOutputs(): -want, +got:\n%s", cmp.Diff(tt.want, got))
+			}
+		})
+	}
+}
+
+func TestIsHTTPURL(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{url: "https://fulcio.example.com", want: true},
+		{url: "http://localhost:8080/rekor", want: true},
+		{url: "fulcio.example.com", want: false},
+		{url: "file:///etc/root.json", want: false},
+		{url: "https://", want: false},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.url, func(t *testing.T) {
+			t.Parallel()
+			if got := isHTTPURL(tt.url); got != tt.want {
+				t.Errorf("isHTTPURL(%q) = %t, want %t", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestValidateSigstoreWithPublish(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    Options
+		publish string
+		wantErr bool
+	}{
+		{
+			name:    "public-good Sigstore",
+			publish: trueStr,
+		},
+		{
+			name:    "custom Fulcio",
+			opts:    Options{InputFulcioURL: "https://fulcio.example.com"},
+			publish: trueStr,
+			wantErr: true,
+		},
+		{
+			name:    "custom TUF root",
+			opts:    Options{InputTUFRoot: "root.json"},
+			publish: trueStr,
+			wantErr: true,
+		},
+		{
+			name: "custom Rekor without publishing",
+			opts: Options{InputRekorURL: "https://rekor.example.com"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(EnvGithubAuthToken, testToken)
+			t.Setenv(EnvInputPublishResults, tt.publish)
+			tt.opts.ScorecardOpts = options.New()
+			err := tt.opts.Validate()
+			if got := errors.Is(err, errSigstoreWithPublish); got != tt.wantErr {
+				t.Errorf("Validate() error: %v, want errSigstoreWithPublish: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
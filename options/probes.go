@@ -0,0 +1,158 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/ossf/scorecard/v5/probes/archived"
+	"github.com/ossf/scorecard/v5/probes/blocksDeleteOnBranches"
+	"github.com/ossf/scorecard/v5/probes/blocksForcePushOnBranches"
+	"github.com/ossf/scorecard/v5/probes/branchProtectionAppliesToAdmins"
+	"github.com/ossf/scorecard/v5/probes/branchesAreProtected"
+	"github.com/ossf/scorecard/v5/probes/codeApproved"
+	"github.com/ossf/scorecard/v5/probes/codeReviewOneReviewers"
+	"github.com/ossf/scorecard/v5/probes/contributorsFromOrgOrCompany"
+	"github.com/ossf/scorecard/v5/probes/createdRecently"
+	"github.com/ossf/scorecard/v5/probes/dependencyUpdateToolConfigured"
+	"github.com/ossf/scorecard/v5/probes/dismissesStaleReviews"
+	"github.com/ossf/scorecard/v5/probes/fuzzed"
+	"github.com/ossf/scorecard/v5/probes/hasBinaryArtifacts"
+	"github.com/ossf/scorecard/v5/probes/hasDangerousWorkflowScriptInjection"
+	"github.com/ossf/scorecard/v5/probes/hasDangerousWorkflowUntrustedCheckout"
+	"github.com/ossf/scorecard/v5/probes/hasFSFOrOSIApprovedLicense"
+	"github.com/ossf/scorecard/v5/probes/hasLicenseFile"
+	"github.com/ossf/scorecard/v5/probes/hasNoGitHubWorkflowPermissionUnknown"
+	"github.com/ossf/scorecard/v5/probes/hasOSVVulnerabilities"
+	"github.com/ossf/scorecard/v5/probes/hasOpenSSFBadge"
+	"github.com/ossf/scorecard/v5/probes/hasPermissiveLicense"
+	"github.com/ossf/scorecard/v5/probes/hasRecentCommits"
+	"github.com/ossf/scorecard/v5/probes/hasReleaseSBOM"
+	"github.com/ossf/scorecard/v5/probes/hasSBOM"
+	"github.com/ossf/scorecard/v5/probes/hasUnverifiedBinaryArtifacts"
+	"github.com/ossf/scorecard/v5/probes/issueActivityByProjectMember"
+	"github.com/ossf/scorecard/v5/probes/jobLevelPermissions"
+	"github.com/ossf/scorecard/v5/probes/packagedWithAutomatedWorkflow"
+	"github.com/ossf/scorecard/v5/probes/pinsDependencies"
+	"github.com/ossf/scorecard/v5/probes/releasesAreSigned"
+	"github.com/ossf/scorecard/v5/probes/releasesHaveProvenance"
+	"github.com/ossf/scorecard/v5/probes/releasesHaveVerifiedProvenance"
+	"github.com/ossf/scorecard/v5/probes/requiresApproversForPullRequests"
+	"github.com/ossf/scorecard/v5/probes/requiresCodeOwnersReview"
+	"github.com/ossf/scorecard/v5/probes/requiresLastPushApproval"
+	"github.com/ossf/scorecard/v5/probes/requiresPRsToChangeCode"
+	"github.com/ossf/scorecard/v5/probes/requiresUpToDateBranches"
+	"github.com/ossf/scorecard/v5/probes/runsStatusChecksBeforeMerging"
+	"github.com/ossf/scorecard/v5/probes/sastToolConfigured"
+	"github.com/ossf/scorecard/v5/probes/sastToolRunsOnAllCommits"
+	"github.com/ossf/scorecard/v5/probes/securityPolicyContainsLinks"
+	"github.com/ossf/scorecard/v5/probes/securityPolicyContainsText"
+	"github.com/ossf/scorecard/v5/probes/securityPolicyContainsVulnerabilityDisclosure"
+	"github.com/ossf/scorecard/v5/probes/securityPolicyPresent"
+	"github.com/ossf/scorecard/v5/probes/testsRunInCI"
+	"github.com/ossf/scorecard/v5/probes/topLevelPermissions"
+	"github.com/ossf/scorecard/v5/probes/unsafeblock"
+	"github.com/ossf/scorecard/v5/probes/webhooksUseSecrets"
+)
+
+// probesAll selects every registered probe in the probes input.
+const probesAll = "all"
+
+var (
+	errUnknownProbe      = errors.New("unknown probe")
+	errProbesWithPublish = errors.New("probes cannot be used with publish_results")
+)
+
+// allProbes are the probes registered in Scorecard. Scorecard keeps its probe
+// registry internal, so the probes are listed here.
+var allProbes = []string{
+	archived.Probe,
+	blocksDeleteOnBranches.Probe,
+	blocksForcePushOnBranches.Probe,
+	branchProtectionAppliesToAdmins.Probe,
+	branchesAreProtected.Probe,
+	codeApproved.Probe,
+	codeReviewOneReviewers.Probe,
+	contributorsFromOrgOrCompany.Probe,
+	createdRecently.Probe,
+	dependencyUpdateToolConfigured.Probe,
+	dismissesStaleReviews.Probe,
+	fuzzed.Probe,
+	hasBinaryArtifacts.Probe,
+	hasDangerousWorkflowScriptInjection.Probe,
+	hasDangerousWorkflowUntrustedCheckout.Probe,
+	hasFSFOrOSIApprovedLicense.Probe,
+	hasLicenseFile.Probe,
+	hasNoGitHubWorkflowPermissionUnknown.Probe,
+	hasOSVVulnerabilities.Probe,
+	hasOpenSSFBadge.Probe,
+	hasPermissiveLicense.Probe,
+	hasRecentCommits.Probe,
+	hasReleaseSBOM.Probe,
+	hasSBOM.Probe,
+	hasUnverifiedBinaryArtifacts.Probe,
+	issueActivityByProjectMember.Probe,
+	jobLevelPermissions.Probe,
+	packagedWithAutomatedWorkflow.Probe,
+	pinsDependencies.Probe,
+	releasesAreSigned.Probe,
+	releasesHaveProvenance.Probe,
+	releasesHaveVerifiedProvenance.Probe,
+	requiresApproversForPullRequests.Probe,
+	requiresCodeOwnersReview.Probe,
+	requiresLastPushApproval.Probe,
+	requiresPRsToChangeCode.Probe,
+	requiresUpToDateBranches.Probe,
+	runsStatusChecksBeforeMerging.Probe,
+	sastToolConfigured.Probe,
+	sastToolRunsOnAllCommits.Probe,
+	securityPolicyContainsLinks.Probe,
+	securityPolicyContainsText.Probe,
+	securityPolicyContainsVulnerabilityDisclosure.Probe,
+	securityPolicyPresent.Probe,
+	testsRunInCI.Probe,
+	topLevelPermissions.Probe,
+	unsafeblock.Probe,
+	webhooksUseSecrets.Probe,
+}
+
+// AllProbes returns the names of the probes Scorecard can run.
+func AllProbes() []string {
+	return slices.Clone(allProbes)
+}
+
+// Probes returns the probes to run, with the names registered in Scorecard,
+// or nil to run checks instead.
+func (o *Options) Probes() ([]string, error) {
+	list := nonEmpty(splitList(o.InputProbes))
+	if len(list) == 1 && strings.EqualFold(list[0], probesAll) {
+		return AllProbes(), nil
+	}
+
+	var probes []string
+	for _, p := range list {
+		i := slices.IndexFunc(allProbes, func(name string) bool { return strings.EqualFold(name, p) })
+		if i < 0 {
+			return nil, fmt.Errorf("%w: %s", errUnknownProbe, p)
+		}
+		if !slices.Contains(probes, allProbes[i]) {
+			probes = append(probes, allProbes[i])
+		}
+	}
+	return probes, nil
+}
@@ -0,0 +1,65 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package options
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestProbes(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name    string
+		input   string
+		want    []string
+		wantErr error
+	}{
+		{
+			name: "checks mode",
+		},
+		{
+			name:  "all probes",
+			input: "ALL",
+			want:  AllProbes(),
+		},
+		{
+			name:  "probes are matched case-insensitively",
+			input: "fuzzed, HasDangerousWorkflowScriptInjection,fuzzed",
+			want:  []string{"fuzzed", "hasDangerousWorkflowScriptInjection"},
+		},
+		{
+			name:    "unknown probe",
+			input:   "fuzzed,noSuchProbe",
+			wantErr: errUnknownProbe,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			o := Options{InputProbes: tt.input}
+			got, err := o.Probes()
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Probes() error: %v, want %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Probes(): -want, +got:\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	_ "embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ossf/scorecard-action/github"
+	"github.com/ossf/scorecard-action/options"
+	scopts "github.com/ossf/scorecard/v5/options"
+)
+
+const defaultGithubAPIURL = "https://api.github.com"
+
+// defaultPolicy is the policy the action image ships as /policy.yml.
+//
+//go:embed policies/template.yml
+var defaultPolicy []byte
+
+var errMissingRepo = errors.New("--repo is required")
+
+// runFlags mirror the workflow inputs and context of a GitHub Actions run.
+type runFlags struct {
+	repo          string
+	event         string
+	ref           string
+	sha           string
+	defaultBranch string
+	format        string
+	output        string
+	fileMode      string
+	policyFile    string
+	prNumber      int
+	private       bool
+}
+
+func newRunCmd() *cobra.Command {
+	f := &runFlags{}
+	cmd := &cobra.Command{
+		Use:   "run --repo <owner/repo>",
+		Short: "Run the action locally, outside GitHub Actions",
+		Long: `Run the action entrypoint outside GitHub Actions, e.g. to reproduce a CI run.
+
+The flags stand in for the GitHub Actions environment: they are turned into the
+GITHUB_* and INPUT_* environment variables and a synthetic event file, then the
+same pipeline as the action runs. Results are never published. A token with read
+access to the repository is read from GITHUB_AUTH_TOKEN.
+
+For pull_request events the working directory is scanned, as in the action.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			// Holds the synthetic event file and the default policy.
+			dir, err := os.MkdirTemp("", "scorecard-action")
+			if err != nil {
+				return fmt.Errorf("creating temp dir: %w", err)
+			}
+			defer os.RemoveAll(dir)
+			if err := f.setEnv(cmd.Context(), dir); err != nil {
+				return err
+			}
+			return runAction()
+		},
+	}
+
+	cmd.Flags().StringVar(&f.repo, "repo", "", "repository to score (owner/repo)")
+	cmd.Flags().StringVar(&f.event, "event", "push", "event that triggered the run, e.g. push or pull_request")
+	cmd.Flags().StringVar(&f.ref, "ref", "", "ref of the run (default: refs/heads/<default branch>)")
+	cmd.Flags().StringVar(&f.sha, "sha", "", "commit to score on refs other than the default branch (default: their head)")
+	cmd.Flags().StringVar(&f.defaultBranch, "default-branch", "",
+		"default branch of the repository (default: looked up with the GitHub API)")
+	cmd.Flags().StringVar(&f.format, "format", "sarif", "results format(s) [json, sarif, probe, intoto, codequality, markdown], comma-separated")
+	cmd.Flags().StringVar(&f.output, "output", "results.sarif", "results file(s), comma-separated, one per format")
+	cmd.Flags().StringVar(&f.fileMode, "file-mode", scopts.FileModeArchive, "method to fetch files from GitHub")
+	cmd.Flags().StringVar(&f.policyFile, "policy", "", "Scorecard policy file (default: the action's policy)")
+	cmd.Flags().IntVar(&f.prNumber, "pr", 0, "pull request number, for pull_request events")
+	cmd.Flags().BoolVar(&f.private, "private", false, "treat the repository as private")
+	return cmd
+}
+
+// setEnv sets up the environment options.New reads in GitHub Actions. The
+// files it needs are written to dir.
+func (f *runFlags) setEnv(ctx context.Context, dir string) error {
+	if f.repo == "" {
+		return errMissingRepo
+	}
+	apiURL := os.Getenv("GITHUB_API_URL")
+	if apiURL == "" {
+		apiURL = defaultGithubAPIURL
+	}
+
+	info := github.RepoInfo{Number: f.prNumber}
+	if f.defaultBranch == "" {
+		var err error
+		info, err = github.NewClient(ctx).ParseFromURL(apiURL, f.repo)
+		if err != nil {
+			return fmt.Errorf("looking up %s, set --default-branch to skip: %w", f.repo, err)
+		}
+		info.Number = f.prNumber
+		if info.Repo.DefaultBranch != nil {
+			f.defaultBranch = *info.Repo.DefaultBranch
+		}
+	} else {
+		fork := false
+		info.Repo.DefaultBranch = &f.defaultBranch
+		info.Repo.Fork = &fork
+	}
+	info.Repo.Private = &f.private
+	if f.ref == "" {
+		f.ref = "refs/heads/" + f.defaultBranch
+	}
+
+	eventPath, err := writeEvent(dir, info)
+	if err != nil {
+		return err
+	}
+	if f.policyFile == "" {
+		f.policyFile = filepath.Join(dir, "policy.yml")
+		if err := os.WriteFile(f.policyFile, defaultPolicy, 0o600); err != nil {
+			return fmt.Errorf("writing default policy: %w", err)
+		}
+	}
+	workspace, err := os.Getwd()
+	if err != nil {
+		return fmt.Errorf("getting working directory: %w", err)
+	}
+
+	env := map[string]string{
+		"GITHUB_API_URL":                  apiURL,
+		options.EnvGithubEventName:        f.event,
+		options.EnvGithubEventPath:        eventPath,
+		options.EnvGithubRef:              f.ref,
+		options.EnvGithubSHA:              f.sha,
+		options.EnvGithubRepository:       f.repo,
+		options.EnvGithubWorkspace:        workspace,
+		options.EnvInputResultsFormat:     f.format,
+		options.EnvInputResultsFile:       f.output,
+		options.EnvInputFileMode:          f.fileMode,
+		options.EnvInputPolicyFile:        f.policyFile,
+		options.EnvInputPublishResults:    "false",
+		options.EnvScorecardPrivateRepo:   strconv.FormatBool(f.private),
+		options.EnvInputInternalRepoToken: os.Getenv(options.EnvGithubAuthToken),
+	}
+	for k, v := range env {
+		if err := os.Setenv(k, v); err != nil {
+			return fmt.Errorf("setting %s: %w", k, err)
+		}
+	}
+	return nil
+}
+
+// writeEvent writes the repository info as the event file of the run.
+func writeEvent(dir string, info github.RepoInfo) (string, error) {
+	contents, err := json.Marshal(info)
+	if err != nil {
+		return "", fmt.Errorf("marshalling event: %w", err)
+	}
+	eventPath := filepath.Join(dir, "event.json")
+	if err := os.WriteFile(eventPath, contents, 0o600); err != nil {
+		return "", fmt.Errorf("writing event: %w", err)
+	}
+	return eventPath, nil
+}
@@ -0,0 +1,97 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/ossf/scorecard-action/options"
+)
+
+//nolint:paralleltest // setEnv modifies the environment
+func TestRunFlags_setEnv(t *testing.T) {
+	// Restore the environment setEnv modifies.
+	for _, k := range []string{
+		"GITHUB_API_URL",
+		options.EnvGithubEventName,
+		options.EnvGithubEventPath,
+		options.EnvGithubRef,
+		options.EnvGithubRepository,
+		options.EnvGithubWorkspace,
+		options.EnvGithubAuthToken,
+		options.EnvInputResultsFormat,
+		options.EnvInputResultsFile,
+		options.EnvInputFileMode,
+		options.EnvInputPolicyFile,
+		options.EnvInputPublishResults,
+		options.EnvScorecardPrivateRepo,
+		options.EnvInputInternalRepoToken,
+	} {
+		t.Setenv(k, os.Getenv(k))
+	}
+	t.Setenv(options.EnvGithubAuthToken, "test-token")
+
+	f := &runFlags{
+		repo:          "ossf/scorecard-action",
+		event:         "push",
+		defaultBranch: "main",
+		format:        "sarif,json",
+		output:        "results.sarif,results.json",
+		fileMode:      "archive",
+		private:       true,
+	}
+	if err := f.setEnv(context.Background(), t.TempDir()); err != nil {
+		t.Fatalf("setEnv(): %v", err)
+	}
+
+	opts, err := options.New()
+	if err != nil {
+		t.Fatalf("options.New(): %v", err)
+	}
+	if opts.DefaultBranch != "main" {
+		t.Errorf("DefaultBranch = %q, want main", opts.DefaultBranch)
+	}
+	if opts.GithubRef != "refs/heads/main" {
+		t.Errorf("GithubRef = %q, want refs/heads/main", opts.GithubRef)
+	}
+	if opts.ScorecardOpts.Repo != "ossf/scorecard-action" {
+		t.Errorf("Repo = %q, want ossf/scorecard-action", opts.ScorecardOpts.Repo)
+	}
+	if opts.PrivateRepoStr != "true" || opts.PublishResults {
+		t.Errorf("private = %s, publish = %t, want a private unpublished run", opts.PrivateRepoStr, opts.PublishResults)
+	}
+	if err := opts.Validate(); err != nil {
+		t.Errorf("Validate(): %v", err)
+	}
+}
+
+func TestRunFlags_setEnvMissingRepo(t *testing.T) {
+	t.Parallel()
+	f := &runFlags{}
+	if err := f.setEnv(context.Background(), t.TempDir()); err == nil {
+		t.Error("setEnv() succeeded without a repository")
+	}
+}
+
+//nolint:paralleltest // runAction reads the environment
+func TestRunAction_returnsErrors(t *testing.T) {
+	t.Setenv("GITHUB_EVENT_NAME", "pull_request_target")
+	if err := runAction(); !errors.Is(err, errPullRequestTarget) {
+		t.Errorf("runAction() error = %v, want %v", err, errPullRequestTarget)
+	}
+}
@@ -0,0 +1,236 @@
+// Copyright 2022 OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package signing
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/sigstore/cosign/v2/pkg/cosign"
+	protocommon "github.com/sigstore/protobuf-specs/gen/pb-go/common/v1"
+	sgbundle "github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore-go/pkg/sign"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+	"github.com/sigstore/sigstore/pkg/signature/kms"
+	sigoptions "github.com/sigstore/sigstore/pkg/signature/options"
+
+	// KMS providers usable in key references, as in cosign.
+	_ "github.com/sigstore/sigstore/pkg/signature/kms/aws"
+	_ "github.com/sigstore/sigstore/pkg/signature/kms/azure"
+	_ "github.com/sigstore/sigstore/pkg/signature/kms/gcp"
+	_ "github.com/sigstore/sigstore/pkg/signature/kms/hashivault"
+)
+
+// DSSE payload types.
+const (
+	// ResultsPayloadType is the payload type of signed JSON results.
+	ResultsPayloadType = "application/vnd.ossf.scorecard.result+json"
+	// InTotoPayloadType is the payload type of signed in-toto statements.
+	InTotoPayloadType = "application/vnd.in-toto+json"
+)
+
+var errUnsupportedKey = errors.New("unsupported signing key")
+
+// signWithKey signs payload as a DSSE envelope with the configured key, and
+// writes it as a Sigstore bundle to bundlePath. The signature is only uploaded
+// to a transparency log when one is configured.
+func (s *Signing) signWithKey(ctx context.Context, payload []byte, payloadType, bundlePath string) error {
+	sv, err := loadSignerVerifier(ctx, s.config.KeyRef, []byte(s.config.KeyPassword))
+	if err != nil {
+		return err
+	}
+	kp, err := newKeypair(sv)
+	if err != nil {
+		return err
+	}
+
+	opts := sign.BundleOptions{Context: ctx}
+	if s.config.RekorURL != "" {
+		opts.TransparencyLogs = append(opts.TransparencyLogs, sign.NewRekor(&sign.RekorOptions{
+			BaseURL: s.config.RekorURL,
+		}))
+	}
+
+	var pb *sgbundle.Bundle
+	for _, backoff := range backoffSchedule {
+		var b sgbundle.Bundle
+		b.Bundle, err = sign.Bundle(&sign.DSSEData{Data: payload, PayloadType: payloadType}, kp, opts)
+		if err == nil {
+			pb = &b
+			break
+		}
+		if s.config.RekorURL == "" {
+			// Nothing to retry without network requests.
+			break
+		}
+		log.Printf("error signing scorecard results: %v\n", err)
+		log.Printf("retrying in %v...\n", backoff)
+		time.Sleep(backoff)
+	}
+	if err != nil {
+		return fmt.Errorf("error signing payload: %w", err)
+	}
+
+	contents, err := pb.MarshalJSON()
+	if err != nil {
+		return fmt.Errorf("marshalling bundle: %w", err)
+	}
+	if err := os.WriteFile(bundlePath, contents, 0o600); err != nil {
+		return fmt.Errorf("writing bundle: %w", err)
+	}
+	return nil
+}
+
+// loadSignerVerifier loads a key reference: a KMS URI such as
+// awskms:///alias/scorecard, a PEM private key file, or PEM contents.
+// PEM keys may be cosign-encrypted, in which case password decrypts them.
+func loadSignerVerifier(ctx context.Context, keyRef string, password []byte) (signature.SignerVerifier, error) {
+	if strings.Contains(keyRef, "://") {
+		sv, err := kms.Get(ctx, keyRef, crypto.SHA256)
+		if err != nil {
+			return nil, fmt.Errorf("loading KMS key: %w", err)
+		}
+		return sv, nil
+	}
+
+	contents := []byte(keyRef)
+	if !strings.HasPrefix(strings.TrimSpace(keyRef), "-----BEGIN") {
+		var err error
+		contents, err = os.ReadFile(keyRef)
+		if err != nil {
+			return nil, fmt.Errorf("reading signing key: %w", err)
+		}
+	}
+
+	block, _ := pem.Decode(contents)
+	if block == nil {
+		return nil, fmt.Errorf("%w: no PEM block found", errUnsupportedKey)
+	}
+	switch block.Type {
+	case cosign.CosignPrivateKeyPemType, cosign.SigstorePrivateKeyPemType:
+		sv, err := cosign.LoadPrivateKey(contents, password, nil)
+		if err != nil {
+			return nil, fmt.Errorf("loading cosign key: %w", err)
+		}
+		return sv, nil
+	default:
+		priv, err := cryptoutils.UnmarshalPEMToPrivateKey(contents, cryptoutils.StaticPasswordFunc(password))
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errUnsupportedKey, err)
+		}
+		sv, err := signature.LoadDefaultSignerVerifier(priv)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %w", errUnsupportedKey, err)
+		}
+		return sv, nil
+	}
+}
+
+// keypair adapts a signature.SignerVerifier to the sign.Keypair used to
+// build Sigstore bundles.
+type keypair struct {
+	sv   signature.SignerVerifier
+	pub  crypto.PublicKey
+	alg  signature.AlgorithmDetails
+	hint []byte
+}
+
+func newKeypair(sv signature.SignerVerifier) (*keypair, error) {
+	pub, err := sv.PublicKey()
+	if err != nil {
+		return nil, fmt.Errorf("getting public key: %w", err)
+	}
+	alg, err := signature.GetDefaultAlgorithmDetails(pub)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", errUnsupportedKey, err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling public key: %w", err)
+	}
+	digest := sha256.Sum256(der)
+	return &keypair{
+		sv:   sv,
+		pub:  pub,
+		alg:  alg,
+		hint: []byte(base64.StdEncoding.EncodeToString(digest[:])),
+	}, nil
+}
+
+func (k *keypair) GetHashAlgorithm() protocommon.HashAlgorithm {
+	return k.alg.GetProtoHashType()
+}
+
+func (k *keypair) GetSigningAlgorithm() protocommon.PublicKeyDetails {
+	return k.alg.GetSignatureAlgorithm()
+}
+
+func (k *keypair) GetHint() []byte {
+	return k.hint
+}
+
+func (k *keypair) GetKeyAlgorithm() string {
+	switch k.alg.GetKeyType() {
+	case signature.ECDSA:
+		return "ECDSA"
+	case signature.RSA:
+		return "RSA"
+	case signature.ED25519:
+		return "ED25519"
+	default:
+		return ""
+	}
+}
+
+func (k *keypair) GetPublicKey() crypto.PublicKey {
+	return k.pub
+}
+
+func (k *keypair) GetPublicKeyPem() (string, error) {
+	contents, err := cryptoutils.MarshalPublicKeyToPEM(k.pub)
+	if err != nil {
+		return "", fmt.Errorf("marshalling public key: %w", err)
+	}
+	return string(contents), nil
+}
+
+// SignData returns the signature over data and the digest that was signed.
+func (k *keypair) SignData(ctx context.Context, data []byte) ([]byte, []byte, error) {
+	digest := data
+	if hf := k.alg.GetHashType(); hf != crypto.Hash(0) {
+		h := hf.New()
+		h.Write(data)
+		digest = h.Sum(nil)
+	}
+	sig, err := k.sv.SignMessage(bytes.NewReader(data), sigoptions.WithContext(ctx))
+	if err != nil {
+		return nil, nil, fmt.Errorf("signing: %w", err)
+	}
+	return sig, digest, nil
+}
@@ -0,0 +1,218 @@
+// Copyright 2022 OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package signing
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+
+	sgbundle "github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+)
+
+// writeKeyPair writes a new ECDSA private and public key as PEM files.
+func writeKeyPair(t *testing.T, dir string) (privPath, pubPath string) {
+	t.Helper()
+	priv, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("generating key: %v", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(priv)
+	if err != nil {
+		t.Fatalf("marshalling private key: %v", err)
+	}
+	privPath = filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(privPath, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		t.Fatalf("writing private key: %v", err)
+	}
+	pubPEM, err := cryptoutils.MarshalPublicKeyToPEM(priv.Public())
+	if err != nil {
+		t.Fatalf("marshalling public key: %v", err)
+	}
+	pubPath = filepath.Join(dir, "key.pub")
+	if err := os.WriteFile(pubPath, pubPEM, 0o600); err != nil {
+		t.Fatalf("writing public key: %v", err)
+	}
+	return privPath, pubPath
+}
+
+func TestSignScorecardResult_key(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	privPath, pubPath := writeKeyPair(t, dir)
+	_, otherPubPath := writeKeyPair(t, t.TempDir())
+
+	results, err := os.ReadFile("testdata/results.json")
+	if err != nil {
+		t.Fatalf("reading testdata: %v", err)
+	}
+	resultsFile := filepath.Join(dir, "results.json")
+	if err := os.WriteFile(resultsFile, results, 0o600); err != nil {
+		t.Fatalf("writing results: %v", err)
+	}
+
+	// Key-based signing doesn't need a GITHUB_TOKEN.
+	s, err := NewWithConfig("", Config{KeyRef: privPath})
+	if err != nil {
+		t.Fatalf("Unexpected error NewWithConfig: %v", err)
+	}
+	if err := s.SignScorecardResult(resultsFile); err != nil {
+		t.Fatalf("SignScorecardResult(): %v", err)
+	}
+	if want := resultsFile + BundleSuffix; s.BundlePath() != want {
+		t.Errorf("BundlePath() = %q, want %q", s.BundlePath(), want)
+	}
+
+	tamperedFile := filepath.Join(dir, "tampered.json")
+	if err := os.WriteFile(tamperedFile, []byte("{}"), 0o600); err != nil {
+		t.Fatalf("writing results: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		resultsFile string
+		publicKey   string
+		wantErr     bool
+	}{
+		{
+			name:        "valid signature",
+			resultsFile: resultsFile,
+			publicKey:   pubPath,
+		},
+		{
+			name:        "wrong key",
+			resultsFile: resultsFile,
+			publicKey:   otherPubPath,
+			wantErr:     true,
+		},
+		{
+			name:        "different results",
+			resultsFile: tamperedFile,
+			publicKey:   pubPath,
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			err := Verify(context.Background(), tt.resultsFile, &VerifyOptions{
+				BundlePath:    s.BundlePath(),
+				PublicKeyPath: tt.publicKey,
+			})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error: %v, wantErr: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func Test_loadSignerVerifier(t *testing.T) {
+	t.Parallel()
+	privPath, _ := writeKeyPair(t, t.TempDir())
+	privPEM, err := os.ReadFile(privPath)
+	if err != nil {
+		t.Fatalf("reading key: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		keyRef  string
+		wantErr bool
+	}{
+		{
+			name:   "PEM file",
+			keyRef: privPath,
+		},
+		{
+			name:   "PEM contents",
+			keyRef: string(privPEM),
+		},
+		{
+			name:    "missing file",
+			keyRef:  "testdata/does-not-exist.pem",
+			wantErr: true,
+		},
+		{
+			name:    "not a key",
+			keyRef:  "testdata/results.json",
+			wantErr: true,
+		},
+		{
+			name:    "unknown KMS",
+			keyRef:  "nosuchkms://key",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			_, err := loadSignerVerifier(context.Background(), tt.keyRef, nil)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("loadSignerVerifier() error: %v, wantErr: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSignAttestation_key(t *testing.T) {
+	t.Parallel()
+	dir := t.TempDir()
+	privPath, pubPath := writeKeyPair(t, dir)
+
+	statementFile := filepath.Join(dir, "results.intoto.json")
+	statement := `{"_type":"https://in-toto.io/Statement/v1","subject":[{"name":"github.com/foo/bar",` +
+		`"digest":{"gitCommit":"68bc59901773ab4c051dfcea0cc4201a1567ab32"}}],` +
+		`"predicateType":"https://scorecard.dev/result/v0.1","predicate":{}}`
+	if err := os.WriteFile(statementFile, []byte(statement), 0o600); err != nil {
+		t.Fatalf("writing statement: %v", err)
+	}
+
+	s, err := NewWithConfig("", Config{KeyRef: privPath})
+	if err != nil {
+		t.Fatalf("Unexpected error NewWithConfig: %v", err)
+	}
+	bundlePath, err := s.SignAttestation(statementFile)
+	if err != nil {
+		t.Fatalf("SignAttestation(): %v", err)
+	}
+	b, err := sgbundle.LoadJSONFromPath(bundlePath)
+	if err != nil {
+		t.Fatalf("loading bundle: %v", err)
+	}
+	envelope, err := b.Envelope()
+	if err != nil {
+		t.Fatalf("reading envelope: %v", err)
+	}
+	if got := envelope.RawEnvelope().PayloadType; got != InTotoPayloadType {
+		t.Errorf("payload type = %q, want %q", got, InTotoPayloadType)
+	}
+	if err := Verify(context.Background(), statementFile, &VerifyOptions{
+		BundlePath:    bundlePath,
+		PublicKeyPath: pubPath,
+	}); err != nil {
+		t.Errorf("Verify(): %v", err)
+	}
+}
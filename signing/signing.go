@@ -31,9 +31,12 @@ import (
 	"strings"
 	"time"
 
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/attest"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/initialize"
 	sigOpts "github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
 	"github.com/sigstore/cosign/v2/cmd/cosign/cli/sign"
 	"github.com/sigstore/cosign/v2/pkg/cosign"
+	sgbundle "github.com/sigstore/sigstore-go/pkg/bundle"
 
 	"github.com/ossf/scorecard-action/options"
 )
@@ -41,6 +44,12 @@ import (
 var (
 	errorEmptyToken   = errors.New("error token empty")
 	errorInvalidToken = errors.New("invalid token")
+	errInvalidBundle  = errors.New("invalid bundle")
+
+	// ErrNoPublishedResult is returned when no results were published for a repository.
+	ErrNoPublishedResult = errors.New("no published results")
+	// ErrPublishFailed is returned when the results could not be uploaded to the Scorecard API.
+	ErrPublishFailed = errors.New("publishing results failed")
 
 	// backoff schedule for interactions with cosign/rekor and our web API.
 	backoffSchedule = []time.Duration{
@@ -50,14 +59,53 @@ var (
 	}
 )
 
+// Config selects the Sigstore deployment results are signed with. Empty
+// fields default to the public-good instance.
+type Config struct {
+	// FulcioURL is the certificate authority issuing the signing certificate.
+	FulcioURL string
+	// RekorURL is the transparency log the signature is uploaded to.
+	RekorURL string
+	// OIDCIssuer is the OIDC provider whose ID token authenticates to Fulcio.
+	OIDCIssuer string
+	// TUFMirror is the TUF repository distributing the deployment's trust roots.
+	TUFMirror string
+	// TUFRoot is the path or URL of the initial TUF root.json for TUFMirror.
+	TUFRoot string
+
+	// KeyRef selects key-based signing instead of keyless signing: a KMS URI
+	// (awskms://, azurekms://, gcpkms://, hashivault://), a PEM private key
+	// file or PEM contents. Results are then only uploaded to RekorURL if set.
+	KeyRef string
+	// KeyPassword decrypts an encrypted PEM private key.
+	KeyPassword string
+}
+
 // Signing is a signing structure.
 type Signing struct {
+	config         Config
 	token          string
+	bundlePath     string
 	rekorTlogIndex int64
+	// idToken is used instead of requesting an OIDC token, for tests.
+	idToken string
 }
 
-// New creates a new Signing instance.
+// New creates a new Signing instance using the public-good Sigstore instance.
 func New(token string) (*Signing, error) {
+	return NewWithConfig(token, Config{})
+}
+
+// NewWithConfig creates a new Signing instance using the given Sigstore deployment.
+func NewWithConfig(token string, config Config) (*Signing, error) {
+	if config.KeyRef != "" {
+		// The token is only needed for keyless signing and publishing.
+		return &Signing{
+			config: config,
+			token:  token,
+		}, nil
+	}
+
 	// Set the default GITHUB_TOKEN, because it's not available by default
 	// in a GitHub Action. We need it for OIDC.
 	if token == "" {
@@ -72,36 +120,55 @@ func New(token string) (*Signing, error) {
 		return nil, fmt.Errorf("error setting GITHUB_TOKEN env var: %w", err)
 	}
 
+	if config.FulcioURL == "" {
+		config.FulcioURL = sigOpts.DefaultFulcioURL
+	}
+	if config.RekorURL == "" {
+		config.RekorURL = sigOpts.DefaultRekorURL
+	}
+	if config.OIDCIssuer == "" {
+		config.OIDCIssuer = sigOpts.DefaultOIDCIssuerURL
+	}
+
 	return &Signing{
-		token: token,
+		config: config,
+		token:  token,
 	}, nil
 }
 
+// BundleSuffix is appended to the results file name to name its Sigstore bundle.
+const BundleSuffix = ".sigstore.json"
+
 // SignScorecardResult signs the results file and uploads the attestation to the Rekor transparency log.
+// The Sigstore bundle is written next to the results file, see BundlePath.
 func (s *Signing) SignScorecardResult(scorecardResultsFile string) error {
-	f, err := os.CreateTemp("", "bundle")
-	if err != nil {
-		return fmt.Errorf("creating temporary bundle file: %w", err)
+	bundlePath := scorecardResultsFile + BundleSuffix
+	if s.config.KeyRef != "" {
+		return s.signFileWithKey(scorecardResultsFile, bundlePath)
+	}
+
+	if err := s.initializeTUF(); err != nil {
+		return err
 	}
-	bundlePath := f.Name()
-	f.Close()
-	defer os.Remove(bundlePath) // clean up
 
 	// Prepare settings for SignBlobCmd.
 	rootOpts := &sigOpts.RootOptions{Timeout: sigOpts.DefaultTimeout} // Just the timeout.
 	keyOpts := sigOpts.KeyOpts{
-		FulcioURL:        sigOpts.DefaultFulcioURL,     // Signing certificate provider.
-		RekorURL:         sigOpts.DefaultRekorURL,      // Transparency log.
-		OIDCIssuer:       sigOpts.DefaultOIDCIssuerURL, // OIDC provider to get ID token to auth for Fulcio.
+		FulcioURL:        s.config.FulcioURL,  // Signing certificate provider.
+		RekorURL:         s.config.RekorURL,   // Transparency log.
+		OIDCIssuer:       s.config.OIDCIssuer, // OIDC provider to get ID token to auth for Fulcio.
 		OIDCClientID:     "sigstore",
+		IDToken:          s.idToken,
 		SkipConfirmation: true, // skip cosign's privacy confirmation prompt as we run non-interactively
 		BundlePath:       bundlePath,
+		NewBundleFormat:  true, // standard Sigstore bundle, verifiable offline
 	}
 
+	var err error
 	for _, backoff := range backoffSchedule {
 		// This command will use the provided OIDCIssuer to authenticate into Fulcio, which will generate the
 		// signing certificate on the scorecard result. This attestation is then uploaded to the Rekor transparency log.
-		// The output bytes (signature) and certificate are discarded since verification can be done with just the payload.
+		// The signature, certificate and transparency log entry are all kept in the bundle.
 		_, err = sign.SignBlobCmd(rootOpts, keyOpts, scorecardResultsFile, true, "", "", true)
 		if err == nil {
 			break
@@ -121,12 +188,135 @@ func (s *Signing) SignScorecardResult(scorecardResultsFile string) error {
 		return err
 	}
 	s.rekorTlogIndex = rekorTlogIndex
+	s.bundlePath = bundlePath
+
+	return nil
+}
+
+func (s *Signing) signFileWithKey(scorecardResultsFile, bundlePath string) error {
+	payload, err := os.ReadFile(scorecardResultsFile)
+	if err != nil {
+		return fmt.Errorf("reading scorecard results: %w", err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sigOpts.DefaultTimeout)
+	defer cancel()
+	if err := s.signWithKey(ctx, payload, ResultsPayloadType, bundlePath); err != nil {
+		return err
+	}
+	if s.config.RekorURL != "" {
+		rekorTlogIndex, err := extractTlogIndex(bundlePath)
+		if err != nil {
+			return err
+		}
+		s.rekorTlogIndex = rekorTlogIndex
+	}
+	s.bundlePath = bundlePath
+	return nil
+}
+
+// SignAttestation signs an in-toto statement as a DSSE envelope, keylessly or
+// with the configured key, and returns the path of the Sigstore bundle written
+// next to it. Keyless signatures are uploaded to the Rekor transparency log.
+func (s *Signing) SignAttestation(statementFile string) (string, error) {
+	bundlePath := statementFile + BundleSuffix
+	if s.config.KeyRef != "" {
+		statement, err := os.ReadFile(statementFile)
+		if err != nil {
+			return "", fmt.Errorf("reading in-toto statement: %w", err)
+		}
+		ctx, cancel := context.WithTimeout(context.Background(), sigOpts.DefaultTimeout)
+		defer cancel()
+		if err := s.signWithKey(ctx, statement, InTotoPayloadType, bundlePath); err != nil {
+			return "", err
+		}
+		return bundlePath, nil
+	}
 
+	if err := s.initializeTUF(); err != nil {
+		return "", err
+	}
+	cmd := attest.AttestBlobCommand{
+		KeyOpts: sigOpts.KeyOpts{
+			FulcioURL:        s.config.FulcioURL,
+			RekorURL:         s.config.RekorURL,
+			OIDCIssuer:       s.config.OIDCIssuer,
+			OIDCClientID:     "sigstore",
+			IDToken:          s.idToken,
+			SkipConfirmation: true,
+			BundlePath:       bundlePath,
+			NewBundleFormat:  true,
+		},
+		StatementPath:  statementFile,
+		TlogUpload:     true,
+		Timeout:        sigOpts.DefaultTimeout,
+		RekorEntryType: "dsse",
+	}
+	var err error
+	for _, backoff := range backoffSchedule {
+		err = cmd.Exec(context.Background(), statementFile)
+		if err == nil {
+			break
+		}
+		log.Printf("error signing scorecard attestation: %v\n", err)
+		log.Printf("retrying in %v...\n", backoff)
+		time.Sleep(backoff)
+	}
+	if err != nil {
+		return "", fmt.Errorf("error signing attestation: %w", err)
+	}
+	return bundlePath, nil
+}
+
+// initializeTUF fetches the trust roots of a private deployment, e.g. to verify Fulcio's SCT.
+func (s *Signing) initializeTUF() error {
+	if s.config.TUFMirror == "" && s.config.TUFRoot == "" {
+		return nil
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), sigOpts.DefaultTimeout)
+	defer cancel()
+	if err := initialize.DoInitialize(ctx, s.config.TUFRoot, s.config.TUFMirror); err != nil {
+		return fmt.Errorf("initializing TUF root: %w", err)
+	}
 	return nil
 }
 
+// BundlePath returns the path of the Sigstore bundle written by SignScorecardResult.
+func (s *Signing) BundlePath() string {
+	return s.bundlePath
+}
+
+// Publish statuses.
+const (
+	PublishStatusPublished = "published"
+	PublishStatusFailed    = "failed"
+	PublishStatusSkipped   = "skipped"
+)
+
+// PublishResult is the outcome of publishing results to the Scorecard API.
+type PublishResult struct {
+	Status    string
+	TlogIndex int64
+	// ResponseCode is the HTTP status of the last attempt, 0 if none was answered.
+	ResponseCode int
+}
+
 // ProcessSignature calls scorecard-api to process & upload signed scorecard results.
+// Upload failures are only logged, see Publish to handle them.
 func (s *Signing) ProcessSignature(jsonPayload []byte, repoName, repoRef string) error {
+	_, err := s.Publish(jsonPayload, repoName, repoRef)
+	if errors.Is(err, ErrPublishFailed) {
+		log.Printf("::warning::Unable to POST scorecard results to webapp: %v. "+
+			"If this issue persists, check the repo issues for more information.\n", err)
+		return nil
+	}
+	return err
+}
+
+// Publish uploads the signed scorecard results to scorecard-api, retrying
+// failures. Once the retries are exhausted, it returns ErrPublishFailed.
+func (s *Signing) Publish(jsonPayload []byte, repoName, repoRef string) (PublishResult, error) {
+	ret := PublishResult{Status: PublishStatusFailed, TlogIndex: s.rekorTlogIndex}
+
 	// Prepare HTTP request body for scorecard-webapp-api call.
 	// TODO: Use the `ScorecardResult` struct from `scorecard-webapp`.
 	resultsPayload := struct {
@@ -143,19 +333,17 @@ func (s *Signing) ProcessSignature(jsonPayload []byte, repoName, repoRef string)
 
 	payloadBytes, err := json.Marshal(resultsPayload)
 	if err != nil {
-		return fmt.Errorf("marshalling json results: %w", err)
+		return ret, fmt.Errorf("marshalling json results: %w", err)
 	}
 
-	apiURL := os.Getenv(options.EnvInputInternalPublishBaseURL)
-	rawURL := fmt.Sprintf("%s/projects/github.com/%s", apiURL, repoName)
-	postURL, err := url.Parse(rawURL)
+	postURL, err := projectURL(repoName)
 	if err != nil {
-		return fmt.Errorf("parsing Scorecard API endpoint: %w", err)
+		return ret, err
 	}
 
 	for _, backoff := range backoffSchedule {
 		// Call scorecard-webapp-api to process and upload signature.
-		err = postResults(postURL, payloadBytes)
+		ret.ResponseCode, err = postResults(postURL, payloadBytes)
 		if err == nil {
 			break
 		}
@@ -166,18 +354,81 @@ func (s *Signing) ProcessSignature(jsonPayload []byte, repoName, repoRef string)
 
 	// retries failed
 	if err != nil {
-		log.Printf("::warning::Unable to POST scorecard results to webapp: %v. "+
-			"If this issue persists, check the repo issues for more information.\n", err)
-		return nil
+		return ret, fmt.Errorf("%w: %w", ErrPublishFailed, err)
 	}
+	ret.Status = PublishStatusPublished
+	return ret, nil
+}
 
-	return nil
+// FetchPublishedResult returns the latest results published for the repository
+// to the Scorecard API, in the json results format, or ErrNoPublishedResult.
+func FetchPublishedResult(repoName string) ([]byte, error) {
+	getURL, err := projectURL(repoName)
+	if err != nil {
+		return nil, err
+	}
+
+	var contents []byte
+	for _, backoff := range backoffSchedule {
+		contents, err = getResults(getURL)
+		if err == nil || errors.Is(err, ErrNoPublishedResult) {
+			break
+		}
+		log.Printf("error fetching published scorecard results: %v\n", err)
+		log.Printf("retrying in %v...\n", backoff)
+		time.Sleep(backoff)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return contents, nil
+}
+
+// projectURL returns the Scorecard API endpoint of the repository.
+func projectURL(repoName string) (*url.URL, error) {
+	apiURL := os.Getenv(options.EnvInputInternalPublishBaseURL)
+	rawURL := fmt.Sprintf("%s/projects/github.com/%s", apiURL, repoName)
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing Scorecard API endpoint: %w", err)
+	}
+	return u, nil
+}
+
+func getResults(endpoint *url.URL) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint.String(), nil)
+	if err != nil {
+		return nil, fmt.Errorf("creating HTTP request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("executing scorecard-api call: %w", err)
+	}
+	defer resp.Body.Close()
+
+	bodyBytes, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return bodyBytes, nil
+	case http.StatusNotFound:
+		return nil, ErrNoPublishedResult
+	default:
+		return nil, fmt.Errorf("http response %d, status: %v, error: %v", resp.StatusCode, resp.Status, string(bodyBytes)) //nolint
+	}
 }
 
-func postResults(endpoint *url.URL, payload []byte) error {
+// postResults returns the HTTP status of the response, 0 if there was none.
+func postResults(endpoint *url.URL, payload []byte) (int, error) {
 	req, err := http.NewRequest("POST", endpoint.String(), bytes.NewBuffer(payload))
 	if err != nil {
-		return fmt.Errorf("creating HTTP request: %w", err)
+		return 0, fmt.Errorf("creating HTTP request: %w", err)
 	}
 	req.Header.Set("Content-Type", "application/json")
 
@@ -189,22 +440,32 @@ func postResults(endpoint *url.URL, payload []byte) error {
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return fmt.Errorf("executing scorecard-api call: %w", err)
+		return 0, fmt.Errorf("executing scorecard-api call: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
 		bodyBytes, err := io.ReadAll(resp.Body)
 		if err != nil {
-			return fmt.Errorf("reading response body: %w", err)
+			return resp.StatusCode, fmt.Errorf("reading response body: %w", err)
 		}
-		return fmt.Errorf("http response %d, status: %v, error: %v", resp.StatusCode, resp.Status, string(bodyBytes)) //nolint
+		return resp.StatusCode, fmt.Errorf("http response %d, status: %v, error: %v", resp.StatusCode, resp.Status, string(bodyBytes)) //nolint
 	}
 
-	return nil
+	return resp.StatusCode, nil
 }
 
+// extractTlogIndex reads the Rekor log index from a Sigstore bundle, falling
+// back to the legacy cosign bundle format.
 func extractTlogIndex(bundlePath string) (int64, error) {
+	if b, err := sgbundle.LoadJSONFromPath(bundlePath); err == nil {
+		entries, err := b.TlogEntries()
+		if err != nil || len(entries) == 0 {
+			return 0, fmt.Errorf("%w: no transparency log entries", errInvalidBundle)
+		}
+		return entries[0].LogIndex(), nil
+	}
+
 	contents, err := os.ReadFile(bundlePath)
 	if err != nil {
 		return 0, fmt.Errorf("reading cosign bundle file: %w", err)
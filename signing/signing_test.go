@@ -17,12 +17,20 @@
 package signing
 
 import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	sigOpts "github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+
 	"github.com/ossf/scorecard-action/options"
 )
 
@@ -141,6 +149,11 @@ func Test_extractTlogIndex(t *testing.T) {
 			bundlePath: "testdata/cosign.bundle",
 			want:       23548006,
 		},
+		{
+			name:       "sigstore bundle",
+			bundlePath: "testdata/results.json.sigstore.json",
+			want:       3,
+		},
 		{
 			name:       "invalid bundle",
 			bundlePath: "testdata/invalid-cosign.bundle",
@@ -232,6 +245,117 @@ func TestProcessSignature_retries(t *testing.T) {
 	}
 }
 
+//nolint:paralleltest // we are using t.Setenv
+func TestPublish(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  int
+		want    PublishResult
+		wantErr error
+	}{
+		{
+			name:   "post succeeded",
+			status: http.StatusCreated,
+			want:   PublishResult{Status: PublishStatusPublished, TlogIndex: 42, ResponseCode: http.StatusCreated},
+		},
+		{
+			name:    "post failed",
+			status:  http.StatusBadRequest,
+			want:    PublishResult{Status: PublishStatusFailed, TlogIndex: 42, ResponseCode: http.StatusBadRequest},
+			wantErr: ErrPublishFailed,
+		},
+	}
+	// use smaller backoffs for the test so they run faster
+	setBackoffs(t, []time.Duration{0, time.Millisecond, 2 * time.Millisecond})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+			}))
+			t.Setenv(options.EnvInputInternalPublishBaseURL, server.URL)
+			t.Cleanup(server.Close)
+
+			//nolint:gosec // dummy credentials
+			s, err := New("ghs_foo")
+			if err != nil {
+				t.Fatalf("Unexpected error New: %v", err)
+			}
+			s.rekorTlogIndex = 42
+			got, err := s.Publish([]byte("{}"), "ossf-tests/scorecard-action", "refs/heads/main")
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Publish() error: %v, want %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Publish() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+//nolint:paralleltest // we are using t.Setenv
+func TestFetchPublishedResult(t *testing.T) {
+	tests := []struct {
+		name          string
+		status        int
+		wantNRequests int
+		wantErr       error
+	}{
+		{
+			name:          "published",
+			status:        http.StatusOK,
+			wantNRequests: 1,
+		},
+		{
+			name:          "never published",
+			status:        http.StatusNotFound,
+			wantNRequests: 1,
+			wantErr:       ErrNoPublishedResult,
+		},
+		{
+			name:          "server error",
+			status:        http.StatusInternalServerError,
+			wantNRequests: 3,
+		},
+	}
+	// use smaller backoffs for the test so they run faster
+	setBackoffs(t, []time.Duration{0, time.Millisecond, 2 * time.Millisecond})
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			jsonPayload, err := os.ReadFile("testdata/results.json")
+			if err != nil {
+				t.Fatalf("Unexpected error reading testdata: %v", err)
+			}
+			var nRequests int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				nRequests++
+				if r.Method != http.MethodGet || r.URL.Path != "/projects/github.com/ossf-tests/scorecard-action" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(tt.status)
+				if tt.status == http.StatusOK {
+					w.Write(jsonPayload) //nolint:errcheck
+				}
+			}))
+			t.Setenv(options.EnvInputInternalPublishBaseURL, server.URL)
+			t.Cleanup(server.Close)
+
+			got, err := FetchPublishedResult("ossf-tests/scorecard-action")
+			switch {
+			case tt.wantErr != nil && !errors.Is(err, tt.wantErr):
+				t.Errorf("FetchPublishedResult() error: %v, want %v", err, tt.wantErr)
+			case tt.status == http.StatusInternalServerError && err == nil:
+				t.Error("FetchPublishedResult() of a failing server: got no error")
+			case tt.status == http.StatusOK && !bytes.Equal(got, jsonPayload):
+				t.Errorf("FetchPublishedResult() = %s, error %v", got, err)
+			}
+			if nRequests != tt.wantNRequests {
+				t.Errorf("FetchPublishedResult() made %d requests, wanted %d", nRequests, tt.wantNRequests)
+			}
+		})
+	}
+}
+
 // temporarily sets the backoffs for a given test.
 func setBackoffs(t *testing.T, newBackoffs []time.Duration) {
 	t.Helper()
@@ -241,3 +365,101 @@ func setBackoffs(t *testing.T, newBackoffs []time.Duration) {
 		backoffSchedule = old
 	})
 }
+
+//nolint:paralleltest // we are replacing the backoffs
+func TestSignScorecardResult_config(t *testing.T) {
+	setBackoffs(t, []time.Duration{0})
+
+	var fulcioRequests []string
+	fulcio := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fulcioRequests = append(fulcioRequests, r.URL.Path)
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(fulcio.Close)
+	var rekorRequests int
+	rekor := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rekorRequests++
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	t.Cleanup(rekor.Close)
+
+	//nolint:gosec // dummy credentials
+	s, err := NewWithConfig("ghs_foo", Config{
+		FulcioURL:  fulcio.URL,
+		RekorURL:   rekor.URL,
+		OIDCIssuer: "https://oidc.example.com",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error NewWithConfig: %v", err)
+	}
+	s.idToken = fakeIDToken(t)
+
+	resultsFile := filepath.Join(t.TempDir(), "results.json")
+	if err := os.WriteFile(resultsFile, []byte("{}"), 0o600); err != nil {
+		t.Fatalf("writing results: %v", err)
+	}
+	if err := s.SignScorecardResult(resultsFile); err == nil {
+		t.Fatal("SignScorecardResult() succeeded against a failing Fulcio")
+	}
+	if len(fulcioRequests) == 0 {
+		t.Error("no request was made to the configured Fulcio")
+	}
+	if rekorRequests != 0 {
+		t.Errorf("made %d Rekor requests without a signing certificate", rekorRequests)
+	}
+}
+
+//nolint:paralleltest // we are replacing the backoffs
+func TestSignScorecardResult_tufMirror(t *testing.T) {
+	setBackoffs(t, []time.Duration{0})
+	t.Setenv("TUF_ROOT", t.TempDir())
+
+	mirror := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(mirror.Close)
+
+	//nolint:gosec // dummy credentials
+	s, err := NewWithConfig("ghs_foo", Config{
+		TUFMirror: mirror.URL,
+		TUFRoot:   "testdata/does-not-exist.json",
+	})
+	if err != nil {
+		t.Fatalf("Unexpected error NewWithConfig: %v", err)
+	}
+	err = s.SignScorecardResult("testdata/results.json")
+	if err == nil || !strings.Contains(err.Error(), "initializing TUF root") {
+		t.Errorf("SignScorecardResult() error: %v, want TUF initialization error", err)
+	}
+}
+
+func TestNewWithConfig_defaults(t *testing.T) {
+	t.Parallel()
+	//nolint:gosec // dummy credentials
+	s, err := NewWithConfig("ghs_foo", Config{RekorURL: "https://rekor.example.com"})
+	if err != nil {
+		t.Fatalf("Unexpected error NewWithConfig: %v", err)
+	}
+	want := Config{
+		FulcioURL:  sigOpts.DefaultFulcioURL,
+		RekorURL:   "https://rekor.example.com",
+		OIDCIssuer: sigOpts.DefaultOIDCIssuerURL,
+	}
+	if s.config != want {
+		t.Errorf("NewWithConfig() config = %+v, want %+v", s.config, want)
+	}
+}
+
+// fakeIDToken returns an unsigned JWT; only Fulcio verifies its signature.
+func fakeIDToken(t *testing.T) string {
+	t.Helper()
+	enc := base64.RawURLEncoding
+	claims, err := json.Marshal(map[string]any{
+		"iss": "https://oidc.example.com",
+		"sub": "repo:ossf-tests/scorecard-action:ref:refs/heads/main",
+		"aud": "sigstore",
+		"exp": time.Now().Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatalf("marshalling claims: %v", err)
+	}
+	return enc.EncodeToString([]byte(`{"alg":"RS256"}`)) + "." + enc.EncodeToString(claims) + ".sig"
+}
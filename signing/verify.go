@@ -0,0 +1,189 @@
+// Copyright 2022 OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package signing
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/secure-systems-lab/go-securesystemslib/dsse"
+	sigOpts "github.com/sigstore/cosign/v2/cmd/cosign/cli/options"
+	"github.com/sigstore/cosign/v2/cmd/cosign/cli/verify"
+	sgbundle "github.com/sigstore/sigstore-go/pkg/bundle"
+	"github.com/sigstore/sigstore/pkg/cryptoutils"
+	"github.com/sigstore/sigstore/pkg/signature"
+)
+
+const (
+	// GitHubActionsOIDCIssuer is the issuer of the OIDC tokens GitHub Actions uses for keyless signing.
+	GitHubActionsOIDCIssuer = "https://token.actions.githubusercontent.com"
+	// GitHubServerURL is the URL of github.com, the default server of the workflows.
+	GitHubServerURL = "https://github.com"
+)
+
+var (
+	errMissingRepository   = errors.New("repository is required")
+	errMissingWorkflowPath = errors.New("workflow path is required")
+	errMissingBundle       = errors.New("bundle path is required")
+	errPayloadMismatch     = errors.New("signed payload does not match results")
+	errNoSignatures        = errors.New("DSSE envelope has no signatures")
+)
+
+// VerifyOptions configures the verification of signed Scorecard results.
+type VerifyOptions struct {
+	// BundlePath is the Sigstore or cosign bundle produced when signing the results.
+	BundlePath string
+	// Repository is the expected "owner/repo" that ran the signing workflow.
+	Repository string
+	// WorkflowPath is the expected workflow file, e.g. ".github/workflows/scorecard.yml".
+	WorkflowPath string
+	// Ref optionally restricts the workflow ref, e.g. "refs/heads/main".
+	Ref string
+	// ServerURL is the GitHub server that ran the workflow, GitHubServerURL if
+	// unset. Set it for workflows run on GitHub Enterprise Server.
+	ServerURL string
+	// OIDCIssuer is the issuer of the workflow's OIDC token,
+	// GitHubActionsOIDCIssuer if unset.
+	OIDCIssuer string
+	// TrustedRootPath is a local Sigstore trusted_root.json. When set, no TUF
+	// lookup is made, allowing verification without network access.
+	TrustedRootPath string
+	// PublicKeyPath verifies results signed with a key instead of keylessly.
+	// Repository and WorkflowPath are then not checked.
+	PublicKeyPath string
+}
+
+// Verify checks that resultsFile was signed by the expected GitHub workflow:
+// the signature over the results, the Fulcio certificate identity, and the
+// transparency log entry embedded in the bundle. Rekor itself is never queried.
+func Verify(ctx context.Context, resultsFile string, o *VerifyOptions) error {
+	if o.BundlePath == "" {
+		return errMissingBundle
+	}
+	if o.PublicKeyPath != "" {
+		if err := verifyWithKey(resultsFile, o.BundlePath, o.PublicKeyPath); err != nil {
+			return fmt.Errorf("verifying %s: %w", resultsFile, err)
+		}
+		return nil
+	}
+	if o.Repository == "" {
+		return errMissingRepository
+	}
+	if o.WorkflowPath == "" {
+		return errMissingWorkflowPath
+	}
+
+	serverURL, issuer := o.ServerURL, o.OIDCIssuer
+	if serverURL == "" {
+		serverURL = GitHubServerURL
+	}
+	if issuer == "" {
+		issuer = GitHubActionsOIDCIssuer
+	}
+
+	cmd := verify.VerifyBlobCmd{
+		KeyOpts: sigOpts.KeyOpts{
+			BundlePath: o.BundlePath,
+		},
+		CertVerifyOptions: sigOpts.CertVerifyOptions{
+			CertIdentityRegexp: certIdentityRegexp(serverURL, o.Repository, o.WorkflowPath, o.Ref),
+			CertOidcIssuer:     issuer,
+		},
+		CertGithubWorkflowRepository: o.Repository,
+		TrustedRootPath:              o.TrustedRootPath,
+		// Verify the tlog entry from the bundle instead of looking it up.
+		Offline: true,
+	}
+	if err := cmd.Exec(ctx, resultsFile); err != nil {
+		return fmt.Errorf("verifying %s: %w", resultsFile, err)
+	}
+	return nil
+}
+
+// verifyWithKey checks a DSSE bundle produced by key-based signing: the
+// envelope signature by the given public key, and that its payload is
+// resultsFile. Transparency log entries, if any, are not checked.
+func verifyWithKey(resultsFile, bundlePath, publicKeyPath string) error {
+	results, err := os.ReadFile(resultsFile)
+	if err != nil {
+		return fmt.Errorf("reading results: %w", err)
+	}
+	pubPEM, err := os.ReadFile(publicKeyPath)
+	if err != nil {
+		return fmt.Errorf("reading public key: %w", err)
+	}
+	pub, err := cryptoutils.UnmarshalPEMToPublicKey(pubPEM)
+	if err != nil {
+		return fmt.Errorf("parsing public key: %w", err)
+	}
+	verifier, err := signature.LoadDefaultVerifier(pub)
+	if err != nil {
+		return fmt.Errorf("loading public key: %w", err)
+	}
+	b, err := sgbundle.LoadJSONFromPath(bundlePath)
+	if err != nil {
+		return fmt.Errorf("loading bundle: %w", err)
+	}
+	envelope, err := b.Envelope()
+	if err != nil {
+		return fmt.Errorf("reading DSSE envelope: %w", err)
+	}
+
+	env := envelope.RawEnvelope()
+	payload, err := env.DecodeB64Payload()
+	if err != nil {
+		return fmt.Errorf("decoding DSSE payload: %w", err)
+	}
+	if len(env.Signatures) == 0 {
+		return errNoSignatures
+	}
+	pae := dsse.PAE(env.PayloadType, payload)
+	for _, sig := range env.Signatures {
+		raw, err := base64.StdEncoding.DecodeString(sig.Sig)
+		if err != nil {
+			return fmt.Errorf("decoding signature: %w", err)
+		}
+		if err := verifier.VerifySignature(bytes.NewReader(raw), bytes.NewReader(pae)); err != nil {
+			return fmt.Errorf("verifying signature: %w", err)
+		}
+	}
+	if !bytes.Equal(payload, results) {
+		return errPayloadMismatch
+	}
+	return nil
+}
+
+// certIdentityRegexp matches the SAN of the Fulcio certificate GitHub Actions
+// workflows are issued: SERVER_URL/OWNER/REPO/PATH@REF.
+func certIdentityRegexp(serverURL, repository, workflowPath, ref string) string {
+	refPattern := ".+"
+	if ref != "" {
+		refPattern = regexp.QuoteMeta(ref)
+	}
+	return fmt.Sprintf("^%s/%s/%s@%s$",
+		regexp.QuoteMeta(strings.TrimSuffix(serverURL, "/")),
+		regexp.QuoteMeta(repository),
+		regexp.QuoteMeta(strings.TrimPrefix(workflowPath, "/")),
+		refPattern,
+	)
+}
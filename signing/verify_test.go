@@ -0,0 +1,272 @@
+// Copyright 2022 OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+//
+// SPDX-License-Identifier: Apache-2.0
+
+package signing
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+)
+
+func Test_certIdentityRegexp(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		name      string
+		serverURL string
+		repo      string
+		workflow  string
+		ref       string
+		san       string
+		want      bool
+	}{
+		{
+			name:     "matching workflow any ref",
+			repo:     "ossf/scorecard",
+			workflow: ".github/workflows/scorecard-analysis.yml",
+			san:      "https://github.com/ossf/scorecard/.github/workflows/scorecard-analysis.yml@refs/heads/main",
+			want:     true,
+		},
+		{
+			name:     "matching workflow and ref",
+			repo:     "ossf/scorecard",
+			workflow: ".github/workflows/scorecard-analysis.yml",
+			ref:      "refs/heads/main",
+			san:      "https://github.com/ossf/scorecard/.github/workflows/scorecard-analysis.yml@refs/heads/main",
+			want:     true,
+		},
+		{
+			name:     "different ref",
+			repo:     "ossf/scorecard",
+			workflow: ".github/workflows/scorecard-analysis.yml",
+			ref:      "refs/heads/main",
+			san:      "https://github.com/ossf/scorecard/.github/workflows/scorecard-analysis.yml@refs/heads/evil",
+			want:     false,
+		},
+		{
+			name:     "different repository",
+			repo:     "ossf/scorecard",
+			workflow: ".github/workflows/scorecard-analysis.yml",
+			san:      "https://github.com/ossf/scorecardx/.github/workflows/scorecard-analysis.yml@refs/heads/main",
+			want:     false,
+		},
+		{
+			name:      "GitHub Enterprise Server",
+			serverURL: "https://ghe.example.com/",
+			repo:      "ossf/scorecard",
+			workflow:  ".github/workflows/scorecard-analysis.yml",
+			san:       "https://ghe.example.com/ossf/scorecard/.github/workflows/scorecard-analysis.yml@refs/heads/main",
+			want:      true,
+		},
+		{
+			name:     "different server",
+			repo:     "ossf/scorecard",
+			workflow: ".github/workflows/scorecard-analysis.yml",
+			san:      "https://ghe.example.com/ossf/scorecard/.github/workflows/scorecard-analysis.yml@refs/heads/main",
+			want:     false,
+		},
+		{
+			name:     "metacharacters are escaped",
+			repo:     "ossf/scorecard",
+			workflow: ".github/workflows/scorecard.yml",
+			san:      "https://github.com/ossf/scorecard/.github/workflows/scorecardxyml@refs/heads/main",
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			serverURL := tt.serverURL
+			if serverURL == "" {
+				serverURL = GitHubServerURL
+			}
+			re := regexp.MustCompile(certIdentityRegexp(serverURL, tt.repo, tt.workflow, tt.ref))
+			if got := re.MatchString(tt.san); got != tt.want {
+				t.Errorf("%s matching %s: got %t, wanted %t", re, tt.san, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerify_invalidInput(t *testing.T) {
+	t.Parallel()
+	valid := VerifyOptions{
+		BundlePath:   "testdata/invalid-cosign.bundle",
+		Repository:   "ossf/scorecard",
+		WorkflowPath: ".github/workflows/scorecard-analysis.yml",
+	}
+	tests := []struct {
+		wantErr error
+		modify  func(*VerifyOptions)
+		name    string
+	}{
+		{
+			name:    "missing bundle",
+			modify:  func(o *VerifyOptions) { o.BundlePath = "" },
+			wantErr: errMissingBundle,
+		},
+		{
+			name:    "missing repository",
+			modify:  func(o *VerifyOptions) { o.Repository = "" },
+			wantErr: errMissingRepository,
+		},
+		{
+			name:    "missing workflow",
+			modify:  func(o *VerifyOptions) { o.WorkflowPath = "" },
+			wantErr: errMissingWorkflowPath,
+		},
+		{
+			name:   "invalid bundle",
+			modify: func(o *VerifyOptions) { o.TrustedRootPath = "testdata/does-not-exist.json" },
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			o := valid
+			tt.modify(&o)
+			err := Verify(context.Background(), "testdata/results.json", &o)
+			if err == nil {
+				t.Fatal("expected error")
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("got error %v, wanted %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+// The keyless bundles sign testdata/results.json with a test Sigstore
+// instance, whose trusted root is next to them, as the
+// ossf-tests/scorecard-action workflow .github/workflows/scorecard.yml on
+// refs/heads/main. The keyless-ghes bundle was issued to the workflow run on
+// https://ghe.example.com.
+func TestVerify_keyless(t *testing.T) {
+	t.Parallel()
+	results := "testdata/results.json"
+	tampered := filepath.Join(t.TempDir(), "results.json")
+	contents, err := os.ReadFile(results)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(tampered, bytes.Replace(contents, []byte(`"score":5.8`), []byte(`"score":9.8`), 1), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	github := VerifyOptions{
+		BundlePath:      "testdata/keyless/results.json.sigstore.json",
+		TrustedRootPath: "testdata/keyless/trusted_root.json",
+		Repository:      "ossf-tests/scorecard-action",
+		WorkflowPath:    ".github/workflows/scorecard.yml",
+	}
+	ghes := github
+	ghes.BundlePath = "testdata/keyless-ghes/results.json.sigstore.json"
+	ghes.TrustedRootPath = "testdata/keyless-ghes/trusted_root.json"
+	ghes.ServerURL = "https://ghe.example.com"
+
+	tests := []struct {
+		modify  func(*VerifyOptions)
+		name    string
+		results string
+		opts    VerifyOptions
+		wantErr bool
+	}{
+		{
+			name:    "valid",
+			opts:    github,
+			results: results,
+		},
+		{
+			name:    "valid with ref",
+			opts:    github,
+			modify:  func(o *VerifyOptions) { o.Ref = "refs/heads/main" },
+			results: results,
+		},
+		{
+			name:    "tampered results",
+			opts:    github,
+			results: tampered,
+			wantErr: true,
+		},
+		{
+			name:    "different repository",
+			opts:    github,
+			modify:  func(o *VerifyOptions) { o.Repository = "ossf-tests/other" },
+			results: results,
+			wantErr: true,
+		},
+		{
+			name:    "different workflow",
+			opts:    github,
+			modify:  func(o *VerifyOptions) { o.WorkflowPath = ".github/workflows/other.yml" },
+			results: results,
+			wantErr: true,
+		},
+		{
+			name:    "different ref",
+			opts:    github,
+			modify:  func(o *VerifyOptions) { o.Ref = "refs/heads/evil" },
+			results: results,
+			wantErr: true,
+		},
+		{
+			name:    "different issuer",
+			opts:    github,
+			modify:  func(o *VerifyOptions) { o.OIDCIssuer = "https://issuer.example.com" },
+			results: results,
+			wantErr: true,
+		},
+		{
+			name:    "untrusted root",
+			opts:    github,
+			modify:  func(o *VerifyOptions) { o.TrustedRootPath = ghes.TrustedRootPath },
+			results: results,
+			wantErr: true,
+		},
+		{
+			name:    "GitHub Enterprise Server",
+			opts:    ghes,
+			results: results,
+		},
+		{
+			name:    "GitHub Enterprise Server without server URL",
+			opts:    ghes,
+			modify:  func(o *VerifyOptions) { o.ServerURL = "" },
+			results: results,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+			o := tt.opts
+			if tt.modify != nil {
+				tt.modify(&o)
+			}
+			err := Verify(context.Background(), tt.results, &o)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verify() error: %v, wantErr: %t", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright OpenSSF Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/ossf/scorecard-action/signing"
+)
+
+func newVerifyCmd() *cobra.Command {
+	o := &signing.VerifyOptions{}
+	cmd := &cobra.Command{
+		Use:   "verify --bundle <bundle> --repository <owner/repo> --workflow <path> <results.json>",
+		Short: "Verify signed Scorecard results",
+		Long: `Verify that a results file was signed by the expected GitHub workflow.
+
+The signature, the signing certificate's identity and the transparency log
+inclusion proof are all checked against the bundle. With --trusted-root, no
+network access is needed.
+
+Results signed with a key are verified with --key instead, which checks the
+DSSE envelope signature and that its payload is the results file.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if err := signing.Verify(cmd.Context(), args[0], o); err != nil {
+				return err //nolint:wrapcheck // already wrapped
+			}
+			fmt.Fprintf(cmd.OutOrStdout(), "verified %s\n", args[0])
+			return nil
+		},
+	}
+
+	cmd.Flags().StringVar(&o.BundlePath, "bundle", o.BundlePath, "Sigstore bundle for the results file")
+	cmd.Flags().StringVar(&o.Repository, "repository", o.Repository, "repository that signed the results (owner/repo)")
+	cmd.Flags().StringVar(&o.WorkflowPath, "workflow", o.WorkflowPath,
+		"workflow that signed the results, e.g. .github/workflows/scorecard.yml")
+	cmd.Flags().StringVar(&o.Ref, "ref", o.Ref, "optional ref the workflow ran on, e.g. refs/heads/main")
+	cmd.Flags().StringVar(&o.ServerURL, "server-url", os.Getenv("GITHUB_SERVER_URL"),
+		"GitHub server that ran the workflow, for GitHub Enterprise Server (default https://github.com)")
+	cmd.Flags().StringVar(&o.OIDCIssuer, "oidc-issuer", o.OIDCIssuer,
+		"issuer of the workflow's OIDC token (default https://token.actions.githubusercontent.com)")
+	cmd.Flags().StringVar(&o.TrustedRootPath, "trusted-root", o.TrustedRootPath,
+		"local Sigstore trusted_root.json, for verification without network access")
+	cmd.Flags().StringVar(&o.PublicKeyPath, "key", o.PublicKeyPath,
+		"PEM public key, for results signed with a key")
+	return cmd
+}